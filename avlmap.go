@@ -0,0 +1,426 @@
+package embedded
+
+// This is a map container with an AVL tree internally - it is more strictly
+// balanced than the red-black tree of Map, so lookups visit fewer items at
+// the cost of more rotations while inserting and removing.
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+func NewAVLMap[TKey MapKeyType, T any](linkField uintptr) Map[TKey, T] {
+	return &embeddedAVLMap[TKey, T]{
+		linkField: linkField,
+	}
+}
+
+type embeddedAVLMap[TKey MapKeyType, T any] struct {
+	root      *T
+	count     int
+	linkField uintptr
+}
+
+func (c *embeddedAVLMap[TKey, T]) getLink(obj *T) *AVLMapLink[TKey, T] {
+	return getAVLMapLink[TKey](obj, c.linkField)
+}
+
+func (c *embeddedAVLMap[TKey, T]) height(obj *T) int {
+	if obj == nil {
+		return 0
+	}
+	return int(c.getLink(obj).height)
+}
+
+func (c *embeddedAVLMap[TKey, T]) size(obj *T) int {
+	if obj == nil {
+		return 0
+	}
+	return c.getLink(obj).position
+}
+
+// findBound returns the first item with a key not less than key, or the first
+// item with a key greater than key when upper is set.
+func (c *embeddedAVLMap[TKey, T]) findBound(key TKey, upper bool) *T {
+	var found *T
+	walk := c.root
+	for walk != nil {
+		walkLink := c.getLink(walk)
+		if walkLink.key < key || (upper && !(key < walkLink.key)) {
+			walk = walkLink.right
+		} else {
+			found = walk
+			walk = walkLink.left
+		}
+	}
+	return found
+}
+
+// findPredecessor returns the last item with a key less than key.
+func (c *embeddedAVLMap[TKey, T]) findPredecessor(key TKey) *T {
+	var found *T
+	walk := c.root
+	for walk != nil {
+		walkLink := c.getLink(walk)
+		if walkLink.key < key {
+			found = walk
+			walk = walkLink.right
+		} else {
+			walk = walkLink.left
+		}
+	}
+	return found
+}
+
+func (c *embeddedAVLMap[TKey, T]) Find(key TKey) *T {
+	return c.FindFirst(key)
+}
+
+func (c *embeddedAVLMap[TKey, T]) FindFirst(key TKey) *T {
+	walk := c.findBound(key, false)
+	if walk != nil && c.GetKey(walk) == key {
+		return walk
+	}
+	return nil
+}
+
+func (c *embeddedAVLMap[TKey, T]) FindNext(cur *T) *T {
+	next := c.Next(cur)
+	if next != nil && c.GetKey(cur) == c.GetKey(next) {
+		return next
+	}
+	return nil
+}
+
+func (c *embeddedAVLMap[TKey, T]) FindLowerInclusive(key TKey) *T {
+	walk := c.findBound(key, false)
+	if walk != nil && c.GetKey(walk) == key {
+		return walk
+	}
+	return c.findPredecessor(key)
+}
+
+func (c *embeddedAVLMap[TKey, T]) FindUpperInclusive(key TKey) *T {
+	return c.findBound(key, false)
+}
+
+func (c *embeddedAVLMap[TKey, T]) FindLowerExclusive(key TKey) *T {
+	return c.findPredecessor(key)
+}
+
+func (c *embeddedAVLMap[TKey, T]) FindUpperExclusive(key TKey) *T {
+	return c.findBound(key, true)
+}
+
+func (c *embeddedAVLMap[TKey, T]) First() *T {
+	var prev *T
+	cur := c.root
+	for cur != nil {
+		prev = cur
+		cur = c.getLink(cur).left
+	}
+	return prev
+}
+
+func (c *embeddedAVLMap[TKey, T]) Last() *T {
+	var prev *T
+	cur := c.root
+	for cur != nil {
+		prev = cur
+		cur = c.getLink(cur).right
+	}
+	return prev
+}
+
+func (c *embeddedAVLMap[TKey, T]) Next(cur *T) *T {
+	curLink := c.getLink(cur)
+	if curLink.right != nil {
+		walk := curLink.right
+		for {
+			walkLink := c.getLink(walk)
+			if walkLink.left == nil {
+				break
+			}
+			walk = walkLink.left
+		}
+		return walk
+	}
+
+	curParent := curLink.parent
+	for curParent != nil && c.getLink(curParent).right == cur {
+		cur = curParent
+		curParent = c.getLink(cur).parent
+	}
+	return curParent
+}
+
+func (c *embeddedAVLMap[TKey, T]) Prev(cur *T) *T {
+	curLink := c.getLink(cur)
+	if curLink.left != nil {
+		walk := curLink.left
+		for {
+			walkLink := c.getLink(walk)
+			if walkLink.right == nil {
+				break
+			}
+			walk = walkLink.right
+		}
+		return walk
+	}
+
+	curParent := curLink.parent
+	for curParent != nil && c.getLink(curParent).left == cur {
+		cur = curParent
+		curParent = c.getLink(cur).parent
+	}
+	return curParent
+}
+
+func (c *embeddedAVLMap[TKey, T]) GetPosition(obj *T) int {
+	position := c.size(c.getLink(obj).left)
+	walk := obj
+	for {
+		parent := c.getLink(walk).parent
+		if parent == nil {
+			break
+		}
+		if parentLink := c.getLink(parent); parentLink.right == walk {
+			position += c.size(parentLink.left) + 1
+		}
+		walk = parent
+	}
+	return position
+}
+
+func (c *embeddedAVLMap[TKey, T]) Position(index int) *T {
+	walk := c.root
+	for walk != nil {
+		walkLink := c.getLink(walk)
+		leftSize := c.size(walkLink.left)
+		if index < leftSize {
+			walk = walkLink.left
+		} else if leftSize < index {
+			index -= leftSize + 1
+			walk = walkLink.right
+		} else {
+			return walk
+		}
+	}
+	return nil
+}
+
+func (c *embeddedAVLMap[TKey, T]) Insert(key TKey, obj *T) *T {
+	var parent *T
+	parentBranch := &c.root
+	walk := c.root
+	for walk != nil {
+		parent = walk
+		walkLink := c.getLink(walk)
+		if key < walkLink.key {
+			parentBranch = &walkLink.left
+		} else {
+			parentBranch = &walkLink.right
+		}
+		walk = *parentBranch
+	}
+
+	*parentBranch = obj
+	objLink := c.getLink(obj)
+	objLink.parent = parent
+	objLink.left = nil
+	objLink.right = nil
+	objLink.height = 1
+	objLink.position = 1
+	objLink.key = key
+	c.count++
+	c.rebalance(parent)
+	return obj
+}
+
+func (c *embeddedAVLMap[TKey, T]) Remove(obj *T) *T {
+	objLink := c.getLink(obj)
+	if objLink.parent == nil && c.root != obj {
+		return nil
+	}
+
+	var rebalanceFrom *T
+	if objLink.left == nil || objLink.right == nil {
+		child := objLink.left
+		if child == nil {
+			child = objLink.right
+		}
+		c.replaceChild(objLink.parent, obj, child)
+		if child != nil {
+			c.getLink(child).parent = objLink.parent
+		}
+		rebalanceFrom = objLink.parent
+	} else {
+		// replace obj with its successor, which has no left child
+		succ := objLink.right
+		for {
+			left := c.getLink(succ).left
+			if left == nil {
+				break
+			}
+			succ = left
+		}
+		succLink := c.getLink(succ)
+		if succ == objLink.right {
+			rebalanceFrom = succ
+		} else {
+			succParent := succLink.parent
+			c.getLink(succParent).left = succLink.right
+			if succLink.right != nil {
+				c.getLink(succLink.right).parent = succParent
+			}
+			succLink.right = objLink.right
+			c.getLink(objLink.right).parent = succ
+			rebalanceFrom = succParent
+		}
+		succLink.left = objLink.left
+		c.getLink(objLink.left).parent = succ
+		succLink.parent = objLink.parent
+		c.replaceChild(objLink.parent, obj, succ)
+	}
+
+	objLink.parent = nil
+	objLink.left = nil
+	objLink.right = nil
+	objLink.height = 0
+	objLink.position = 0
+	c.count--
+	c.rebalance(rebalanceFrom)
+	return obj
+}
+
+func (c *embeddedAVLMap[TKey, T]) RemoveFirst() *T {
+	head := c.First()
+	if head == nil {
+		return nil
+	}
+	return c.Remove(head)
+}
+
+func (c *embeddedAVLMap[TKey, T]) RemoveLast() *T {
+	tail := c.Last()
+	if tail == nil {
+		return nil
+	}
+	return c.Remove(tail)
+}
+
+func (c *embeddedAVLMap[TKey, T]) Move(cur *T, newKey TKey) {
+	c.Remove(cur)
+	c.Insert(newKey, cur)
+}
+
+func (c *embeddedAVLMap[TKey, T]) GetKey(obj *T) TKey {
+	return c.getLink(obj).key
+}
+
+func (c *embeddedAVLMap[TKey, T]) Count() int {
+	return c.count
+}
+
+func (c *embeddedAVLMap[TKey, T]) IsEmpty() bool {
+	return c.count == 0
+}
+
+func (c *embeddedAVLMap[TKey, T]) RemoveAll() {
+	c.root = nil
+	c.count = 0
+}
+
+func (c *embeddedAVLMap[TKey, T]) IsContained(obj *T) bool {
+	walk := c.FindFirst(c.GetKey(obj))
+	for walk != nil {
+		if walk == obj {
+			return true
+		}
+		walk = c.FindNext(walk)
+	}
+	return false
+}
+
+func (c *embeddedAVLMap[TKey, T]) replaceChild(parent *T, oldChild *T, newChild *T) {
+	if parent == nil {
+		c.root = newChild
+	} else if parentLink := c.getLink(parent); parentLink.left == oldChild {
+		parentLink.left = newChild
+	} else {
+		parentLink.right = newChild
+	}
+}
+
+func (c *embeddedAVLMap[TKey, T]) update(obj *T) {
+	objLink := c.getLink(obj)
+	leftHeight := c.height(objLink.left)
+	rightHeight := c.height(objLink.right)
+	if leftHeight < rightHeight {
+		objLink.height = int8(rightHeight + 1)
+	} else {
+		objLink.height = int8(leftHeight + 1)
+	}
+	objLink.position = c.size(objLink.left) + c.size(objLink.right) + 1
+}
+
+func (c *embeddedAVLMap[TKey, T]) rotateLeft(cur *T) *T {
+	curLink := c.getLink(cur)
+	right := curLink.right
+	rightLink := c.getLink(right)
+	inner := rightLink.left
+
+	curLink.right = inner
+	if inner != nil {
+		c.getLink(inner).parent = cur
+	}
+	rightLink.parent = curLink.parent
+	c.replaceChild(curLink.parent, cur, right)
+	rightLink.left = cur
+	curLink.parent = right
+
+	c.update(cur)
+	c.update(right)
+	return right
+}
+
+func (c *embeddedAVLMap[TKey, T]) rotateRight(cur *T) *T {
+	curLink := c.getLink(cur)
+	left := curLink.left
+	leftLink := c.getLink(left)
+	inner := leftLink.right
+
+	curLink.left = inner
+	if inner != nil {
+		c.getLink(inner).parent = cur
+	}
+	leftLink.parent = curLink.parent
+	c.replaceChild(curLink.parent, cur, left)
+	leftLink.right = cur
+	curLink.parent = left
+
+	c.update(cur)
+	c.update(left)
+	return left
+}
+
+// rebalance restores the heights, sizes and balance of every item from walk
+// up to the root.
+func (c *embeddedAVLMap[TKey, T]) rebalance(walk *T) {
+	for walk != nil {
+		c.update(walk)
+		walkLink := c.getLink(walk)
+		balance := c.height(walkLink.left) - c.height(walkLink.right)
+		if balance > 1 {
+			leftLink := c.getLink(walkLink.left)
+			if c.height(leftLink.left) < c.height(leftLink.right) {
+				c.rotateLeft(walkLink.left)
+			}
+			walk = c.rotateRight(walk)
+		} else if balance < -1 {
+			rightLink := c.getLink(walkLink.right)
+			if c.height(rightLink.right) < c.height(rightLink.left) {
+				c.rotateRight(walkLink.right)
+			}
+			walk = c.rotateLeft(walk)
+		}
+		walk = c.getLink(walk).parent
+	}
+}
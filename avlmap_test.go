@@ -0,0 +1,52 @@
+package embedded_test
+
+import (
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type avlMapEntry struct {
+	data int
+	link embedded.AVLMapLink[int, avlMapEntry]
+}
+
+var avlMapEntryLinkField = unsafe.Offsetof(avlMapEntry{}.link)
+
+func TestEmbeddedAVLMap(t *testing.T) {
+	testEmbeddedMap(t, func() embedded.Map[int, avlMapEntry] {
+		return embedded.NewAVLMap[int, avlMapEntry](avlMapEntryLinkField)
+	}, func(data int) *avlMapEntry {
+		return &avlMapEntry{data: data}
+	}, func(obj *avlMapEntry) int {
+		return obj.data
+	})
+}
+
+func TestEmbeddedAVLMapLinkSize(t *testing.T) {
+	if linkSize, mapLinkSize := unsafe.Sizeof(avlMapEntry{}.link), unsafe.Sizeof(mapEntry{}.link); linkSize > mapLinkSize {
+		t.Fatalf("AVL map link is larger than the map link (AVL %d > map %d)", linkSize, mapLinkSize)
+	}
+}
+
+func BenchmarkEmbeddedAVLMap_Insert(b *testing.B) {
+	m := embedded.NewAVLMap[int, avlMapEntry](avlMapEntryLinkField)
+	b.ReportAllocs()
+	b.ReportMetric(float64(unsafe.Sizeof(avlMapEntry{}.link)), "link-bytes")
+	for i := 0; i < b.N; i++ {
+		m.Insert(i, &avlMapEntry{data: i})
+	}
+}
+
+func BenchmarkEmbeddedAVLMap_Find(b *testing.B) {
+	const mapSize = 1 << 16
+	m := embedded.NewAVLMap[int, avlMapEntry](avlMapEntryLinkField)
+	for i := 0; i < mapSize; i++ {
+		m.Insert(i, &avlMapEntry{data: i})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Find(i % mapSize)
+	}
+}
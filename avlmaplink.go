@@ -0,0 +1,22 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// AVLMapLink is a link to the AVL map container. An AVL tree of n items is
+// less than 1.45 log2(n+2) high, so an int8 holds the height of any tree and
+// the link is no larger than a MapLink.
+type AVLMapLink[TKey, T any] struct {
+	key      TKey
+	parent   *T
+	left     *T
+	right    *T
+	height   int8
+	position int
+}
+
+func getAVLMapLink[TKey, T any](obj *T, linkFieldOfs uintptr) *AVLMapLink[TKey, T] {
+	u := unsafe.Add(unsafe.Pointer(obj), linkFieldOfs)
+	return (*AVLMapLink[TKey, T])(u)
+}
@@ -0,0 +1,226 @@
+package embedded
+
+// This is a combination double-linked list and hash table container holding
+// at most a fixed number of items - inserting into a full container drops
+// either the incoming item or one already in the container, as chosen by its
+// overflow policy. The Insert methods return the dropped item: nil when
+// nothing was dropped, or the incoming item itself when it was rejected.
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+type BoundedHashListMap[TKey HashMapKeyType, T any] interface {
+	TableInterface
+	ListInterface[TKey, T]
+
+	RemoveAllByKey(key TKey)
+	RemoveAllByUniqueKey(key TKey)
+
+	Capacity() int
+	IsFull() bool
+}
+
+// NewBoundedHashListMapStatic creates a hash list map with a static table
+// size, holding at most capacity items and configured by opts. The onOverflow
+// callback is only used by the OverflowCallback policy.
+func NewBoundedHashListMapStatic[TKey HashMapKeyType, T any](linkField uintptr, tableSize int, capacity int, policy OverflowPolicy, onOverflow func(incoming *T) *T, opts ...HashOption) BoundedHashListMap[TKey, T] {
+	return &embeddedBoundedHashListMap[TKey, T]{
+		hashListMap: NewHashListMapStatic[TKey, T](linkField, tableSize, opts...),
+		overflow:    newOverflowHandler(capacity, policy, onOverflow),
+	}
+}
+
+// NewBoundedHashListMapDynamic creates a hash list map with a dynamic table
+// size, holding at most capacity items and configured by opts. The onOverflow
+// callback is only used by the OverflowCallback policy.
+func NewBoundedHashListMapDynamic[TKey HashMapKeyType, T any](linkField uintptr, capacity int, policy OverflowPolicy, onOverflow func(incoming *T) *T, opts ...HashOption) BoundedHashListMap[TKey, T] {
+	return &embeddedBoundedHashListMap[TKey, T]{
+		hashListMap: NewHashListMapDynamic[TKey, T](linkField, opts...),
+		overflow:    newOverflowHandler(capacity, policy, onOverflow),
+	}
+}
+
+type embeddedBoundedHashListMap[TKey HashMapKeyType, T any] struct {
+	hashListMap HashListMap[TKey, T]
+	overflow    overflowHandler[T]
+}
+
+// makeRoom drops an item if the container is full, returning the dropped
+// item.
+func (c *embeddedBoundedHashListMap[TKey, T]) makeRoom(cur *T) *T {
+	victim := c.overflow.victim(c.hashListMap.Count(), c.hashListMap.First(), c.hashListMap.Last(), cur)
+	if victim != nil && victim != cur {
+		if !c.hashListMap.IsContained(victim) {
+			panic("overflow victim is not contained in this container")
+		}
+		c.hashListMap.Remove(victim)
+	}
+	return victim
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) First() *T {
+	return c.hashListMap.First()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Last() *T {
+	return c.hashListMap.Last()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Next(cur *T) *T {
+	return c.hashListMap.Next(cur)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Prev(cur *T) *T {
+	return c.hashListMap.Prev(cur)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Position(index int) *T {
+	return c.hashListMap.Position(index)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Count() int {
+	return c.hashListMap.Count()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Remove(obj *T) *T {
+	return c.hashListMap.Remove(obj)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) RemoveFirst() *T {
+	return c.hashListMap.RemoveFirst()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) RemoveLast() *T {
+	return c.hashListMap.RemoveLast()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) RemoveAll() {
+	c.hashListMap.RemoveAll()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) RemoveAllByKey(key TKey) {
+	c.hashListMap.RemoveAllByKey(key)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) RemoveAllByUniqueKey(key TKey) {
+	c.hashListMap.RemoveAllByUniqueKey(key)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) InsertFirst(key TKey, cur *T) *T {
+	victim := c.makeRoom(cur)
+	if victim != cur {
+		c.hashListMap.InsertFirst(key, cur)
+	}
+	return victim
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) InsertLast(key TKey, cur *T) *T {
+	victim := c.makeRoom(cur)
+	if victim != cur {
+		c.hashListMap.InsertLast(key, cur)
+	}
+	return victim
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) InsertAfter(key TKey, prev, cur *T) *T {
+	var prevPrev *T
+	if prev != nil {
+		prevPrev = c.hashListMap.Prev(prev)
+	}
+	victim := c.makeRoom(cur)
+	if victim == cur {
+		return victim
+	}
+	if victim != nil && victim == prev {
+		prev = prevPrev
+	}
+	c.hashListMap.InsertAfter(key, prev, cur)
+	return victim
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) InsertBefore(key TKey, after, cur *T) *T {
+	var afterNext *T
+	if after != nil {
+		afterNext = c.hashListMap.Next(after)
+	}
+	victim := c.makeRoom(cur)
+	if victim == cur {
+		return victim
+	}
+	if victim != nil && victim == after {
+		after = afterNext
+	}
+	c.hashListMap.InsertBefore(key, after, cur)
+	return victim
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Move(obj *T, newKey TKey) {
+	c.hashListMap.Move(obj, newKey)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) MoveFirst(cur *T) {
+	c.hashListMap.MoveFirst(cur)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) MoveLast(cur *T) {
+	c.hashListMap.MoveLast(cur)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) MoveAfter(dest, cur *T) {
+	c.hashListMap.MoveAfter(dest, cur)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) MoveBefore(dest, cur *T) {
+	c.hashListMap.MoveBefore(dest, cur)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) FindFirst(key TKey) *T {
+	return c.hashListMap.FindFirst(key)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) FindNext(prevResult *T) *T {
+	return c.hashListMap.FindNext(prevResult)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) GetKey(obj *T) TKey {
+	return c.hashListMap.GetKey(obj)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) GetTableSize() int {
+	return c.hashListMap.GetTableSize()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) GetTableUsed() int {
+	return c.hashListMap.GetTableUsed()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Stats() HashTableStats {
+	return c.hashListMap.Stats()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Reserve(count int) {
+	c.hashListMap.Reserve(count)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Compact() {
+	c.hashListMap.Compact()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Resize(tableSize int) {
+	c.hashListMap.Resize(tableSize)
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) Capacity() int {
+	return c.overflow.capacity
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) IsEmpty() bool {
+	return c.hashListMap.IsEmpty()
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) IsFull() bool {
+	return c.hashListMap.Count() >= c.overflow.capacity
+}
+
+func (c *embeddedBoundedHashListMap[TKey, T]) IsContained(cur *T) bool {
+	return c.hashListMap.IsContained(cur)
+}
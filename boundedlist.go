@@ -0,0 +1,186 @@
+package embedded
+
+// This is a double-linked list container holding at most a fixed number of
+// items - inserting into a full list drops either the incoming item or one
+// already in the list, as chosen by its overflow policy. The Insert methods
+// return the dropped item: nil when nothing was dropped, or the incoming item
+// itself when it was rejected. An item returned by the overflow callback is
+// checked with IsContained, which for a list only tells whether the item is in
+// some list, so the callback must not return an item of another list.
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+type BoundedList[T any] interface {
+	First() *T
+	Last() *T
+	Next(cur *T) *T
+	Prev(cur *T) *T
+	Position(index int) *T
+
+	Remove(obj *T) *T
+	RemoveFirst() *T
+	RemoveLast() *T
+	RemoveAll()
+
+	InsertFirst(cur *T) *T
+	InsertLast(cur *T) *T
+	InsertAfter(prev, cur *T) *T
+	InsertBefore(after, cur *T) *T
+
+	MoveFirst(cur *T)
+	MoveLast(cur *T)
+	MoveAfter(dest, cur *T)
+	MoveBefore(dest, cur *T)
+
+	Capacity() int
+	Count() int
+	IsEmpty() bool
+	IsFull() bool
+	IsContained(cur *T) bool
+}
+
+// NewBoundedList creates a list holding at most capacity items. The
+// onOverflow callback is only used by the OverflowCallback policy.
+func NewBoundedList[T any](linkField uintptr, capacity int, policy OverflowPolicy, onOverflow func(incoming *T) *T) BoundedList[T] {
+	return &embeddedBoundedList[T]{
+		list:     NewList[T](linkField),
+		overflow: newOverflowHandler(capacity, policy, onOverflow),
+	}
+}
+
+type embeddedBoundedList[T any] struct {
+	list     List[T]
+	overflow overflowHandler[T]
+}
+
+// makeRoom drops an item if the list is full, returning the dropped item.
+func (c *embeddedBoundedList[T]) makeRoom(cur *T) *T {
+	victim := c.overflow.victim(c.list.Count(), c.list.First(), c.list.Last(), cur)
+	if victim != nil && victim != cur {
+		if !c.list.IsContained(victim) {
+			panic("overflow victim is not contained in this list")
+		}
+		c.list.Remove(victim)
+	}
+	return victim
+}
+
+func (c *embeddedBoundedList[T]) First() *T {
+	return c.list.First()
+}
+
+func (c *embeddedBoundedList[T]) Last() *T {
+	return c.list.Last()
+}
+
+func (c *embeddedBoundedList[T]) Next(cur *T) *T {
+	return c.list.Next(cur)
+}
+
+func (c *embeddedBoundedList[T]) Prev(cur *T) *T {
+	return c.list.Prev(cur)
+}
+
+func (c *embeddedBoundedList[T]) Position(index int) *T {
+	return c.list.Position(index)
+}
+
+func (c *embeddedBoundedList[T]) Remove(obj *T) *T {
+	return c.list.Remove(obj)
+}
+
+func (c *embeddedBoundedList[T]) RemoveFirst() *T {
+	return c.list.RemoveFirst()
+}
+
+func (c *embeddedBoundedList[T]) RemoveLast() *T {
+	return c.list.RemoveLast()
+}
+
+func (c *embeddedBoundedList[T]) RemoveAll() {
+	c.list.RemoveAll()
+}
+
+func (c *embeddedBoundedList[T]) InsertFirst(cur *T) *T {
+	victim := c.makeRoom(cur)
+	if victim != cur {
+		c.list.InsertFirst(cur)
+	}
+	return victim
+}
+
+func (c *embeddedBoundedList[T]) InsertLast(cur *T) *T {
+	victim := c.makeRoom(cur)
+	if victim != cur {
+		c.list.InsertLast(cur)
+	}
+	return victim
+}
+
+func (c *embeddedBoundedList[T]) InsertAfter(prev, cur *T) *T {
+	var prevPrev *T
+	if prev != nil {
+		prevPrev = c.list.Prev(prev)
+	}
+	victim := c.makeRoom(cur)
+	if victim == cur {
+		return victim
+	}
+	if victim != nil && victim == prev {
+		prev = prevPrev
+	}
+	c.list.InsertAfter(prev, cur)
+	return victim
+}
+
+func (c *embeddedBoundedList[T]) InsertBefore(after, cur *T) *T {
+	var afterNext *T
+	if after != nil {
+		afterNext = c.list.Next(after)
+	}
+	victim := c.makeRoom(cur)
+	if victim == cur {
+		return victim
+	}
+	if victim != nil && victim == after {
+		after = afterNext
+	}
+	c.list.InsertBefore(after, cur)
+	return victim
+}
+
+func (c *embeddedBoundedList[T]) MoveFirst(cur *T) {
+	c.list.MoveFirst(cur)
+}
+
+func (c *embeddedBoundedList[T]) MoveLast(cur *T) {
+	c.list.MoveLast(cur)
+}
+
+func (c *embeddedBoundedList[T]) MoveAfter(dest, cur *T) {
+	c.list.MoveAfter(dest, cur)
+}
+
+func (c *embeddedBoundedList[T]) MoveBefore(dest, cur *T) {
+	c.list.MoveBefore(dest, cur)
+}
+
+func (c *embeddedBoundedList[T]) Capacity() int {
+	return c.overflow.capacity
+}
+
+func (c *embeddedBoundedList[T]) Count() int {
+	return c.list.Count()
+}
+
+func (c *embeddedBoundedList[T]) IsEmpty() bool {
+	return c.list.IsEmpty()
+}
+
+func (c *embeddedBoundedList[T]) IsFull() bool {
+	return c.list.Count() >= c.overflow.capacity
+}
+
+func (c *embeddedBoundedList[T]) IsContained(cur *T) bool {
+	return c.list.IsContained(cur)
+}
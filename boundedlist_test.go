@@ -0,0 +1,130 @@
+package embedded_test
+
+import (
+	"testing"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+func TestEmbeddedBoundedList(t *testing.T) {
+	const capacity = 4
+	newEntries := func(c embedded.BoundedList[listEntry]) {
+		for i := 0; i < capacity; i++ {
+			if dropped := c.InsertLast(&listEntry{data: i}); dropped != nil {
+				t.Fatal("unexpected item dropped below capacity")
+			}
+		}
+		if !c.IsFull() || c.Capacity() != capacity {
+			t.Fatal("embedded list should be full")
+		}
+	}
+
+	c := embedded.NewBoundedList[listEntry](listEntryLinkField, capacity, embedded.OverflowReject, nil)
+	newEntries(c)
+	incoming := &listEntry{data: capacity}
+	if dropped := c.InsertLast(incoming); dropped != incoming {
+		t.Fatal("incoming item not rejected")
+	}
+	if c.IsContained(incoming) || c.Count() != capacity {
+		t.Fatal("rejected item was inserted")
+	}
+	testEmbeddedBoundedListContents(t, c, []int{0, 1, 2, 3})
+
+	c = embedded.NewBoundedList[listEntry](listEntryLinkField, capacity, embedded.OverflowEvictFirst, nil)
+	newEntries(c)
+	if dropped := c.InsertLast(&listEntry{data: capacity}); dropped == nil || dropped.data != 0 {
+		t.Fatal("first item not evicted")
+	}
+	testEmbeddedBoundedListContents(t, c, []int{1, 2, 3, 4})
+	// the evicted item was the insertion point, so its successor takes its place
+	if dropped := c.InsertBefore(c.First(), &listEntry{data: -1}); dropped == nil || dropped.data != 1 {
+		t.Fatal("first item not evicted")
+	}
+	testEmbeddedBoundedListContents(t, c, []int{-1, 2, 3, 4})
+
+	c = embedded.NewBoundedList[listEntry](listEntryLinkField, capacity, embedded.OverflowEvictLast, nil)
+	newEntries(c)
+	if dropped := c.InsertFirst(&listEntry{data: -1}); dropped == nil || dropped.data != 3 {
+		t.Fatal("last item not evicted")
+	}
+	testEmbeddedBoundedListContents(t, c, []int{-1, 0, 1, 2})
+	if dropped := c.InsertAfter(c.Last(), &listEntry{data: 3}); dropped == nil || dropped.data != 2 {
+		t.Fatal("last item not evicted")
+	}
+	testEmbeddedBoundedListContents(t, c, []int{-1, 0, 1, 3})
+
+	// evict the smallest item, unless the incoming one is smaller still
+	var smallest embedded.BoundedList[listEntry]
+	smallest = embedded.NewBoundedList[listEntry](listEntryLinkField, capacity, embedded.OverflowCallback, func(incoming *listEntry) *listEntry {
+		var victim *listEntry
+		for cur := smallest.First(); cur != nil; cur = smallest.Next(cur) {
+			if victim == nil || cur.data < victim.data {
+				victim = cur
+			}
+		}
+		if incoming.data < victim.data {
+			return nil
+		}
+		return victim
+	})
+	newEntries(smallest)
+	if dropped := smallest.InsertFirst(&listEntry{data: 10}); dropped == nil || dropped.data != 0 {
+		t.Fatal("unexpected item evicted by callback")
+	}
+	incoming = &listEntry{data: 0}
+	if dropped := smallest.InsertLast(incoming); dropped != incoming {
+		t.Fatal("incoming item not rejected by callback")
+	}
+	testEmbeddedBoundedListContents(t, smallest, []int{10, 1, 2, 3})
+
+	smallest.RemoveFirst()
+	if smallest.IsFull() || smallest.InsertLast(incoming) != nil {
+		t.Fatal("unexpected item dropped below capacity")
+	}
+}
+
+func TestEmbeddedBoundedListInvalid(t *testing.T) {
+	expectPanic(t, func() {
+		embedded.NewBoundedList[listEntry](listEntryLinkField, 0, embedded.OverflowReject, nil)
+	})
+	expectPanic(t, func() {
+		embedded.NewBoundedList[listEntry](listEntryLinkField, 1, embedded.OverflowCallback, nil)
+	})
+}
+
+func TestEmbeddedBoundedListOverflowCallback(t *testing.T) {
+	const capacity = 2
+	var victim *listEntry
+	c := embedded.NewBoundedList[listEntry](listEntryLinkField, capacity, embedded.OverflowCallback, func(incoming *listEntry) *listEntry {
+		return victim
+	})
+	for i := 0; i < capacity; i++ {
+		c.InsertLast(&listEntry{data: i})
+	}
+
+	incoming := &listEntry{data: capacity}
+	if dropped := c.InsertLast(incoming); dropped != incoming {
+		t.Fatal("incoming item not rejected when the callback returns nil")
+	}
+	testEmbeddedBoundedListContents(t, c, []int{0, 1})
+
+	victim = &listEntry{data: -1}
+	expectPanic(t, func() {
+		c.InsertLast(incoming)
+	})
+	testEmbeddedBoundedListContents(t, c, []int{0, 1})
+}
+
+func testEmbeddedBoundedListContents(t *testing.T, c embedded.BoundedList[listEntry], expected []int) {
+	t.Helper()
+	if actualCount := c.Count(); actualCount != len(expected) {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, len(expected))
+	}
+	cur := c.First()
+	for _, data := range expected {
+		if cur == nil || cur.data != data {
+			t.Fatal("expected entry not found")
+		}
+		cur = c.Next(cur)
+	}
+}
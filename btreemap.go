@@ -0,0 +1,569 @@
+package embedded
+
+// This is a map container with a B+ tree internally - items and their keys are
+// packed together into nodes, so lookups and bound searches binary search small
+// key arrays instead of chasing a pointer per level as embedded.Map does.
+// The tree nodes are owned by the container, but this cointainer does not take
+// ownership of its contents, so the application must remove items manually.
+
+const (
+	btreeMapNodeMax = 32
+	btreeMapNodeMin = btreeMapNodeMax / 2
+)
+
+func NewBTreeMap[TKey MapKeyType, T any](linkField uintptr) Map[TKey, T] {
+	return &embeddedBTreeMap[TKey, T]{
+		linkField: linkField,
+	}
+}
+
+type embeddedBTreeMap[TKey MapKeyType, T any] struct {
+	root      *btreeMapNode[TKey, T]
+	linkField uintptr
+}
+
+// btreeMapNode is either a leaf, holding items and their keys, or a branch,
+// holding children. In a branch, keys[i] separates children[i-1] from
+// children[i]; keys[0] is unused.
+type btreeMapNode[TKey MapKeyType, T any] struct {
+	parent   *btreeMapNode[TKey, T]
+	count    int
+	keys     []TKey
+	items    []*T
+	children []*btreeMapNode[TKey, T]
+	prev     *btreeMapNode[TKey, T]
+	next     *btreeMapNode[TKey, T]
+}
+
+func newBTreeMapLeaf[TKey MapKeyType, T any]() *btreeMapNode[TKey, T] {
+	return &btreeMapNode[TKey, T]{
+		keys:  make([]TKey, 0, btreeMapNodeMax+1),
+		items: make([]*T, 0, btreeMapNodeMax+1),
+	}
+}
+
+func newBTreeMapBranch[TKey MapKeyType, T any]() *btreeMapNode[TKey, T] {
+	return &btreeMapNode[TKey, T]{
+		keys:     make([]TKey, 0, btreeMapNodeMax+1),
+		children: make([]*btreeMapNode[TKey, T], 0, btreeMapNodeMax+1),
+	}
+}
+
+func (n *btreeMapNode[TKey, T]) isLeaf() bool {
+	return n.children == nil
+}
+
+func (n *btreeMapNode[TKey, T]) size() int {
+	if n.isLeaf() {
+		return len(n.items)
+	}
+	return len(n.children)
+}
+
+func (n *btreeMapNode[TKey, T]) indexOfItem(obj *T, key TKey) int {
+	for i := btreeMapLowerBound(n.keys, key); i < len(n.items); i++ {
+		if n.items[i] == obj {
+			return i
+		}
+	}
+	return -1
+}
+
+func (n *btreeMapNode[TKey, T]) indexOfChild(child *btreeMapNode[TKey, T]) int {
+	for i, c := range n.children {
+		if c == child {
+			return i
+		}
+	}
+	return -1
+}
+
+func btreeMapLowerBound[TKey MapKeyType](keys []TKey, key TKey) int {
+	lo, hi := 0, len(keys)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if keys[mid] < key {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+func btreeMapUpperBound[TKey MapKeyType](keys []TKey, key TKey) int {
+	lo, hi := 0, len(keys)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if key < keys[mid] {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+func btreeMapInsertAt[E any](s []E, i int, v E) []E {
+	s = append(s, v)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
+
+func btreeMapRemoveAt[E any](s []E, i int) []E {
+	copy(s[i:], s[i+1:])
+	var zero E
+	s[len(s)-1] = zero
+	return s[:len(s)-1]
+}
+
+func (c *embeddedBTreeMap[TKey, T]) getLink(obj *T) *BTreeMapLink[TKey, T] {
+	return getBTreeMapLink[TKey](obj, c.linkField)
+}
+
+// findBound returns the first item with a key not less than key, or the first
+// item with a key greater than key when upper is set.
+func (c *embeddedBTreeMap[TKey, T]) findBound(key TKey, upper bool) *T {
+	node := c.root
+	if node == nil {
+		return nil
+	}
+
+	bound := btreeMapLowerBound[TKey]
+	if upper {
+		bound = btreeMapUpperBound[TKey]
+	}
+
+	for !node.isLeaf() {
+		node = node.children[bound(node.keys[1:], key)]
+	}
+
+	i := bound(node.keys, key)
+	if i < len(node.items) {
+		return node.items[i]
+	}
+	if node.next != nil {
+		return node.next.items[0]
+	}
+	return nil
+}
+
+func (c *embeddedBTreeMap[TKey, T]) Find(key TKey) *T {
+	return c.FindFirst(key)
+}
+
+func (c *embeddedBTreeMap[TKey, T]) FindFirst(key TKey) *T {
+	walk := c.findBound(key, false)
+	if walk != nil && c.GetKey(walk) == key {
+		return walk
+	}
+	return nil
+}
+
+func (c *embeddedBTreeMap[TKey, T]) FindNext(cur *T) *T {
+	next := c.Next(cur)
+	if next != nil && c.GetKey(cur) == c.GetKey(next) {
+		return next
+	}
+	return nil
+}
+
+func (c *embeddedBTreeMap[TKey, T]) FindLowerInclusive(key TKey) *T {
+	walk := c.findBound(key, false)
+	if walk == nil {
+		return c.Last()
+	}
+	if c.GetKey(walk) == key {
+		return walk
+	}
+	return c.Prev(walk)
+}
+
+func (c *embeddedBTreeMap[TKey, T]) FindUpperInclusive(key TKey) *T {
+	return c.findBound(key, false)
+}
+
+func (c *embeddedBTreeMap[TKey, T]) FindLowerExclusive(key TKey) *T {
+	walk := c.findBound(key, false)
+	if walk == nil {
+		return c.Last()
+	}
+	return c.Prev(walk)
+}
+
+func (c *embeddedBTreeMap[TKey, T]) FindUpperExclusive(key TKey) *T {
+	return c.findBound(key, true)
+}
+
+func (c *embeddedBTreeMap[TKey, T]) First() *T {
+	node := c.root
+	if node == nil {
+		return nil
+	}
+	for !node.isLeaf() {
+		node = node.children[0]
+	}
+	return node.items[0]
+}
+
+func (c *embeddedBTreeMap[TKey, T]) Last() *T {
+	node := c.root
+	if node == nil {
+		return nil
+	}
+	for !node.isLeaf() {
+		node = node.children[len(node.children)-1]
+	}
+	return node.items[len(node.items)-1]
+}
+
+func (c *embeddedBTreeMap[TKey, T]) Next(cur *T) *T {
+	curLink := c.getLink(cur)
+	leaf := curLink.leaf
+	i := leaf.indexOfItem(cur, curLink.key) + 1
+	if i < len(leaf.items) {
+		return leaf.items[i]
+	}
+	if leaf.next != nil {
+		return leaf.next.items[0]
+	}
+	return nil
+}
+
+func (c *embeddedBTreeMap[TKey, T]) Prev(cur *T) *T {
+	curLink := c.getLink(cur)
+	leaf := curLink.leaf
+	i := leaf.indexOfItem(cur, curLink.key) - 1
+	if i >= 0 {
+		return leaf.items[i]
+	}
+	if leaf.prev != nil {
+		return leaf.prev.items[len(leaf.prev.items)-1]
+	}
+	return nil
+}
+
+func (c *embeddedBTreeMap[TKey, T]) GetPosition(obj *T) int {
+	objLink := c.getLink(obj)
+	node := objLink.leaf
+	position := node.indexOfItem(obj, objLink.key)
+	for parent := node.parent; parent != nil; node, parent = parent, parent.parent {
+		for _, child := range parent.children {
+			if child == node {
+				break
+			}
+			position += child.count
+		}
+	}
+	return position
+}
+
+func (c *embeddedBTreeMap[TKey, T]) Position(index int) *T {
+	if index < 0 || index >= c.Count() {
+		return nil
+	}
+
+	node := c.root
+	for !node.isLeaf() {
+		for _, child := range node.children {
+			if index < child.count {
+				node = child
+				break
+			}
+			index -= child.count
+		}
+	}
+	return node.items[index]
+}
+
+func (c *embeddedBTreeMap[TKey, T]) Insert(key TKey, obj *T) *T {
+	if c.root == nil {
+		c.root = newBTreeMapLeaf[TKey, T]()
+	}
+
+	node := c.root
+	for !node.isLeaf() {
+		node.count++
+		node = node.children[btreeMapUpperBound(node.keys[1:], key)]
+	}
+
+	i := btreeMapUpperBound(node.keys, key)
+	node.keys = btreeMapInsertAt(node.keys, i, key)
+	node.items = btreeMapInsertAt(node.items, i, obj)
+	node.count++
+
+	objLink := c.getLink(obj)
+	objLink.key = key
+	objLink.leaf = node
+
+	if len(node.items) > btreeMapNodeMax {
+		c.split(node)
+	}
+	return obj
+}
+
+func (c *embeddedBTreeMap[TKey, T]) Remove(obj *T) *T {
+	objLink := c.getLink(obj)
+	node := objLink.leaf
+	if node == nil {
+		return nil
+	}
+
+	i := node.indexOfItem(obj, objLink.key)
+	node.keys = btreeMapRemoveAt(node.keys, i)
+	node.items = btreeMapRemoveAt(node.items, i)
+	objLink.leaf = nil
+
+	for walk := node; walk != nil; walk = walk.parent {
+		walk.count--
+	}
+
+	c.rebalance(node)
+	return obj
+}
+
+func (c *embeddedBTreeMap[TKey, T]) RemoveFirst() *T {
+	head := c.First()
+	if head == nil {
+		return nil
+	}
+	return c.Remove(head)
+}
+
+func (c *embeddedBTreeMap[TKey, T]) RemoveLast() *T {
+	tail := c.Last()
+	if tail == nil {
+		return nil
+	}
+	return c.Remove(tail)
+}
+
+func (c *embeddedBTreeMap[TKey, T]) Move(cur *T, newKey TKey) {
+	c.Remove(cur)
+	c.Insert(newKey, cur)
+}
+
+func (c *embeddedBTreeMap[TKey, T]) GetKey(obj *T) TKey {
+	return c.getLink(obj).key
+}
+
+func (c *embeddedBTreeMap[TKey, T]) Count() int {
+	if c.root == nil {
+		return 0
+	}
+	return c.root.count
+}
+
+func (c *embeddedBTreeMap[TKey, T]) IsEmpty() bool {
+	return c.root == nil
+}
+
+func (c *embeddedBTreeMap[TKey, T]) RemoveAll() {
+	node := c.root
+	if node == nil {
+		return
+	}
+	for !node.isLeaf() {
+		node = node.children[0]
+	}
+	for ; node != nil; node = node.next {
+		for _, obj := range node.items {
+			c.getLink(obj).leaf = nil
+		}
+	}
+	c.root = nil
+}
+
+func (c *embeddedBTreeMap[TKey, T]) IsContained(obj *T) bool {
+	node := c.getLink(obj).leaf
+	if node == nil {
+		return false
+	}
+	for node.parent != nil {
+		node = node.parent
+	}
+	return node == c.root
+}
+
+func (c *embeddedBTreeMap[TKey, T]) split(node *btreeMapNode[TKey, T]) {
+	half := len(node.keys) / 2
+
+	var right *btreeMapNode[TKey, T]
+	if node.isLeaf() {
+		right = newBTreeMapLeaf[TKey, T]()
+		right.keys = append(right.keys, node.keys[half:]...)
+		right.items = append(right.items, node.items[half:]...)
+		for i := half; i < len(node.items); i++ {
+			node.items[i] = nil
+		}
+		node.keys = node.keys[:half]
+		node.items = node.items[:half]
+		for _, item := range right.items {
+			c.getLink(item).leaf = right
+		}
+		right.count = len(right.items)
+
+		right.prev = node
+		right.next = node.next
+		if node.next != nil {
+			node.next.prev = right
+		}
+		node.next = right
+	} else {
+		right = newBTreeMapBranch[TKey, T]()
+		right.keys = append(right.keys, node.keys[half:]...)
+		right.children = append(right.children, node.children[half:]...)
+		for i := half; i < len(node.children); i++ {
+			node.children[i] = nil
+		}
+		node.keys = node.keys[:half]
+		node.children = node.children[:half]
+		for _, child := range right.children {
+			child.parent = right
+			right.count += child.count
+		}
+	}
+	node.count -= right.count
+
+	separator := right.keys[0]
+	parent := node.parent
+	if parent == nil {
+		parent = newBTreeMapBranch[TKey, T]()
+		parent.keys = append(parent.keys, separator, separator)
+		parent.children = append(parent.children, node, right)
+		parent.count = node.count + right.count
+		node.parent = parent
+		c.root = parent
+	} else {
+		i := parent.indexOfChild(node) + 1
+		parent.keys = btreeMapInsertAt(parent.keys, i, separator)
+		parent.children = btreeMapInsertAt(parent.children, i, right)
+	}
+	right.parent = parent
+
+	if len(parent.children) > btreeMapNodeMax {
+		c.split(parent)
+	}
+}
+
+func (c *embeddedBTreeMap[TKey, T]) rebalance(node *btreeMapNode[TKey, T]) {
+	parent := node.parent
+	if parent == nil {
+		if node.isLeaf() {
+			if len(node.items) == 0 {
+				c.root = nil
+			}
+		} else if len(node.children) == 1 {
+			c.root = node.children[0]
+			c.root.parent = nil
+		}
+		return
+	}
+
+	if node.size() >= btreeMapNodeMin {
+		return
+	}
+
+	i := parent.indexOfChild(node)
+	if i > 0 && parent.children[i-1].size() > btreeMapNodeMin {
+		c.borrowLeft(parent, i)
+		return
+	}
+	if i+1 < len(parent.children) && parent.children[i+1].size() > btreeMapNodeMin {
+		c.borrowRight(parent, i)
+		return
+	}
+
+	if i > 0 {
+		c.merge(parent, i-1)
+	} else {
+		c.merge(parent, i)
+	}
+	c.rebalance(parent)
+}
+
+func (c *embeddedBTreeMap[TKey, T]) borrowLeft(parent *btreeMapNode[TKey, T], i int) {
+	node := parent.children[i]
+	left := parent.children[i-1]
+	last := len(left.keys) - 1
+	key := left.keys[last]
+	left.keys = btreeMapRemoveAt(left.keys, last)
+
+	if node.isLeaf() {
+		item := left.items[last]
+		left.items = btreeMapRemoveAt(left.items, last)
+		node.keys = btreeMapInsertAt(node.keys, 0, key)
+		node.items = btreeMapInsertAt(node.items, 0, item)
+		c.getLink(item).leaf = node
+		left.count--
+		node.count++
+	} else {
+		child := left.children[last]
+		left.children = btreeMapRemoveAt(left.children, last)
+		node.keys = btreeMapInsertAt(node.keys, 0, key)
+		node.keys[1] = parent.keys[i]
+		node.children = btreeMapInsertAt(node.children, 0, child)
+		child.parent = node
+		left.count -= child.count
+		node.count += child.count
+	}
+	parent.keys[i] = key
+}
+
+func (c *embeddedBTreeMap[TKey, T]) borrowRight(parent *btreeMapNode[TKey, T], i int) {
+	node := parent.children[i]
+	right := parent.children[i+1]
+
+	if node.isLeaf() {
+		key := right.keys[0]
+		item := right.items[0]
+		right.keys = btreeMapRemoveAt(right.keys, 0)
+		right.items = btreeMapRemoveAt(right.items, 0)
+		node.keys = append(node.keys, key)
+		node.items = append(node.items, item)
+		c.getLink(item).leaf = node
+		right.count--
+		node.count++
+		parent.keys[i+1] = right.keys[0]
+	} else {
+		separator := right.keys[1]
+		child := right.children[0]
+		right.keys = btreeMapRemoveAt(right.keys, 0)
+		right.children = btreeMapRemoveAt(right.children, 0)
+		node.keys = append(node.keys, parent.keys[i+1])
+		node.children = append(node.children, child)
+		child.parent = node
+		right.count -= child.count
+		node.count += child.count
+		parent.keys[i+1] = separator
+	}
+}
+
+func (c *embeddedBTreeMap[TKey, T]) merge(parent *btreeMapNode[TKey, T], i int) {
+	left := parent.children[i]
+	right := parent.children[i+1]
+
+	if left.isLeaf() {
+		left.keys = append(left.keys, right.keys...)
+		left.items = append(left.items, right.items...)
+		for _, item := range right.items {
+			c.getLink(item).leaf = left
+		}
+		left.next = right.next
+		if right.next != nil {
+			right.next.prev = left
+		}
+	} else {
+		left.keys = append(left.keys, parent.keys[i+1])
+		left.keys = append(left.keys, right.keys[1:]...)
+		left.children = append(left.children, right.children...)
+		for _, child := range right.children {
+			child.parent = left
+		}
+	}
+	left.count += right.count
+
+	parent.keys = btreeMapRemoveAt(parent.keys, i+1)
+	parent.children = btreeMapRemoveAt(parent.children, i+1)
+}
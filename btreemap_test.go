@@ -0,0 +1,82 @@
+package embedded_test
+
+import (
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type btreeMapEntry struct {
+	data int
+	link embedded.BTreeMapLink[int, btreeMapEntry]
+}
+
+var btreeMapEntryLinkField = unsafe.Offsetof(btreeMapEntry{}.link)
+
+func TestEmbeddedBTreeMap(t *testing.T) {
+	testEmbeddedMap(t, func() embedded.Map[int, btreeMapEntry] {
+		return embedded.NewBTreeMap[int, btreeMapEntry](btreeMapEntryLinkField)
+	}, func(data int) *btreeMapEntry {
+		return &btreeMapEntry{data: data}
+	}, func(obj *btreeMapEntry) int {
+		return obj.data
+	})
+}
+
+func TestEmbeddedBTreeMapRemoveAll(t *testing.T) {
+	const testSize = 1000
+	m := embedded.NewBTreeMap[int, btreeMapEntry](btreeMapEntryLinkField)
+	var entries []*btreeMapEntry
+	for i := 0; i < testSize; i++ {
+		entry := &btreeMapEntry{data: i}
+		entries = append(entries, entry)
+		m.Insert(i, entry)
+	}
+	m.RemoveAll()
+	m.Insert(0, &btreeMapEntry{data: 0})
+	for _, entry := range entries {
+		if m.IsContained(entry) {
+			t.Fatal("item is still contained after RemoveAll")
+		}
+		if m.Remove(entry) != nil {
+			t.Fatal("removed an item which was removed by RemoveAll")
+		}
+	}
+	if m.Count() != 1 {
+		t.Fatalf("unexpected count after RemoveAll (actual %d != expected 1)", m.Count())
+	}
+}
+
+func BenchmarkEmbeddedBTreeMap_Insert(b *testing.B) {
+	m := embedded.NewBTreeMap[int, btreeMapEntry](btreeMapEntryLinkField)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.Insert(i, &btreeMapEntry{data: i})
+	}
+}
+
+func BenchmarkEmbeddedBTreeMap_Find(b *testing.B) {
+	const mapSize = 1 << 16
+	m := embedded.NewBTreeMap[int, btreeMapEntry](btreeMapEntryLinkField)
+	for i := 0; i < mapSize; i++ {
+		m.Insert(i, &btreeMapEntry{data: i})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Find(i % mapSize)
+	}
+}
+
+func BenchmarkEmbeddedBTreeMap_Scan(b *testing.B) {
+	const mapSize = 1 << 16
+	m := embedded.NewBTreeMap[int, btreeMapEntry](btreeMapEntryLinkField)
+	for i := 0; i < mapSize; i++ {
+		m.Insert(i, &btreeMapEntry{data: i})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for cur := m.First(); cur != nil; cur = m.Next(cur) {
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// BTreeMapLink is a link to the B-tree map container
+type BTreeMapLink[TKey MapKeyType, T any] struct {
+	key  TKey
+	leaf *btreeMapNode[TKey, T]
+}
+
+func getBTreeMapLink[TKey MapKeyType, T any](obj *T, linkFieldOfs uintptr) *BTreeMapLink[TKey, T] {
+	u := unsafe.Add(unsafe.Pointer(obj), linkFieldOfs)
+	return (*BTreeMapLink[TKey, T])(u)
+}
@@ -0,0 +1,187 @@
+package embedded
+
+// This is a circular double-linked list container - it allows for linear
+// iteration over its contents. The list is closed by a sentinel link held in
+// the container, so an item can be removed with only its own link (see
+// CircularListLink.Unlink), for instance from a destructor or callback which
+// does not know the list. In exchange, the list does not track its count, so
+// Count, Position and IsContained walk the list, and Remove, like Unlink, acts
+// on whichever list holds the item - removing an item of another list through
+// this one unlinks it from that list without error.
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+type CircularList[T any] interface {
+	First() *T
+	Last() *T
+	Next(cur *T) *T
+	Prev(cur *T) *T
+	Position(index int) *T
+
+	Remove(obj *T) *T
+	RemoveFirst() *T
+	RemoveLast() *T
+	RemoveAll()
+
+	InsertFirst(cur *T) *T
+	InsertLast(cur *T) *T
+	InsertAfter(prev, cur *T) *T
+	InsertBefore(after, cur *T) *T
+
+	MoveFirst(cur *T)
+	MoveLast(cur *T)
+	MoveAfter(dest, cur *T)
+	MoveBefore(dest, cur *T)
+
+	Count() int
+	IsEmpty() bool
+	IsContained(cur *T) bool
+}
+
+func NewCircularList[T any](linkField uintptr) CircularList[T] {
+	c := &embeddedCircularList[T]{
+		linkField: linkField,
+	}
+	c.sentinel.prev = &c.sentinel
+	c.sentinel.next = &c.sentinel
+	return c
+}
+
+type embeddedCircularList[T any] struct {
+	sentinel  CircularListLink[T]
+	linkField uintptr
+}
+
+func (c *embeddedCircularList[T]) getLink(obj *T) *CircularListLink[T] {
+	return getCircularListLink(obj, c.linkField)
+}
+
+func (c *embeddedCircularList[T]) getItem(link *CircularListLink[T]) *T {
+	if link == &c.sentinel {
+		return nil
+	}
+	return link.getItem(c.linkField)
+}
+
+func (c *embeddedCircularList[T]) First() *T {
+	return c.getItem(c.sentinel.next)
+}
+
+func (c *embeddedCircularList[T]) Last() *T {
+	return c.getItem(c.sentinel.prev)
+}
+
+func (c *embeddedCircularList[T]) Next(cur *T) *T {
+	return c.getItem(c.getLink(cur).next)
+}
+
+func (c *embeddedCircularList[T]) Prev(cur *T) *T {
+	return c.getItem(c.getLink(cur).prev)
+}
+
+func (c *embeddedCircularList[T]) Position(index int) *T {
+	cur := c.First()
+	for cur != nil && index > 0 {
+		cur = c.Next(cur)
+		index--
+	}
+	return cur
+}
+
+func (c *embeddedCircularList[T]) Remove(obj *T) *T {
+	c.getLink(obj).Unlink()
+	return obj
+}
+
+func (c *embeddedCircularList[T]) RemoveFirst() *T {
+	head := c.First()
+	if head == nil {
+		return nil
+	}
+	return c.Remove(head)
+}
+
+func (c *embeddedCircularList[T]) RemoveLast() *T {
+	tail := c.Last()
+	if tail == nil {
+		return nil
+	}
+	return c.Remove(tail)
+}
+
+func (c *embeddedCircularList[T]) RemoveAll() {
+	for cur := c.Last(); cur != nil; cur = c.Last() {
+		c.Remove(cur)
+	}
+}
+
+func (c *embeddedCircularList[T]) InsertFirst(cur *T) *T {
+	c.getLink(cur).insertAfter(&c.sentinel)
+	return cur
+}
+
+func (c *embeddedCircularList[T]) InsertLast(cur *T) *T {
+	c.getLink(cur).insertAfter(c.sentinel.prev)
+	return cur
+}
+
+func (c *embeddedCircularList[T]) InsertAfter(prev, cur *T) *T {
+	if prev == nil {
+		return c.InsertFirst(cur)
+	}
+	c.getLink(cur).insertAfter(c.getLink(prev))
+	return cur
+}
+
+func (c *embeddedCircularList[T]) InsertBefore(after, cur *T) *T {
+	if after == nil {
+		return c.InsertLast(cur)
+	}
+	c.getLink(cur).insertAfter(c.getLink(after).prev)
+	return cur
+}
+
+func (c *embeddedCircularList[T]) MoveFirst(cur *T) {
+	c.Remove(cur)
+	c.InsertFirst(cur)
+}
+
+func (c *embeddedCircularList[T]) MoveLast(cur *T) {
+	c.Remove(cur)
+	c.InsertLast(cur)
+}
+
+func (c *embeddedCircularList[T]) MoveAfter(dest, cur *T) {
+	c.Remove(cur)
+	c.InsertAfter(dest, cur)
+}
+
+func (c *embeddedCircularList[T]) MoveBefore(dest, cur *T) {
+	c.Remove(cur)
+	c.InsertBefore(dest, cur)
+}
+
+func (c *embeddedCircularList[T]) Count() int {
+	count := 0
+	for link := c.sentinel.next; link != &c.sentinel; link = link.next {
+		count++
+	}
+	return count
+}
+
+func (c *embeddedCircularList[T]) IsEmpty() bool {
+	return c.sentinel.next == &c.sentinel
+}
+
+func (c *embeddedCircularList[T]) IsContained(cur *T) bool {
+	curLink := c.getLink(cur)
+	if !curLink.IsLinked() {
+		return false
+	}
+	for link := c.sentinel.next; link != &c.sentinel; link = link.next {
+		if link == curLink {
+			return true
+		}
+	}
+	return false
+}
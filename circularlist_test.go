@@ -0,0 +1,102 @@
+package embedded_test
+
+import (
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type circularListEntry struct {
+	data int
+	link embedded.CircularListLink[circularListEntry]
+}
+
+var circularListEntryLinkField = unsafe.Offsetof(circularListEntry{}.link)
+
+func TestEmbeddedCircularList(t *testing.T) {
+	const testSize = 5500
+	c := embedded.NewCircularList[circularListEntry](circularListEntryLinkField)
+	if !c.IsEmpty() || c.First() != nil || c.Last() != nil || c.RemoveFirst() != nil {
+		t.Fatal("new embedded list is not empty")
+	}
+
+	entries := make([]*circularListEntry, testSize)
+	for i := range entries {
+		entries[i] = c.InsertLast(&circularListEntry{data: i})
+	}
+	if actualCount := c.Count(); actualCount != testSize {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize)
+	}
+
+	// items detach themselves without access to the list
+	for i := 0; i < testSize; i += 2 {
+		entries[i].link.Unlink()
+		if entries[i].link.IsLinked() || c.IsContained(entries[i]) {
+			t.Fatal("embedded list reports that unlinked item is present")
+		}
+		entries[i].link.Unlink()
+	}
+
+	expected := 1
+	for cur := c.First(); cur != nil; cur = c.Next(cur) {
+		if cur.data != expected {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.data, expected)
+		}
+		expected += 2
+	}
+	expected = testSize - 1
+	for cur := c.Last(); cur != nil; cur = c.Prev(cur) {
+		if cur.data != expected {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.data, expected)
+		}
+		expected -= 2
+	}
+	if actualCount := c.Count(); actualCount != testSize/2 {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize/2)
+	}
+	if actualEntry := c.Position(2); actualEntry != entries[5] {
+		t.Fatal("item not found at expected position")
+	}
+
+	c.InsertFirst(entries[0])
+	c.InsertAfter(entries[0], entries[2])
+	c.InsertBefore(entries[1], entries[4])
+	c.MoveLast(entries[0])
+	c.MoveBefore(entries[1], entries[2])
+	c.MoveAfter(entries[testSize-1], entries[6])
+	c.MoveFirst(entries[6])
+	for i, expected := range []int{6, 4, 2, 1, 3} {
+		if actualEntry := c.Position(i); actualEntry != entries[expected] {
+			t.Fatalf("item not found at expected position %d", i)
+		}
+	}
+	if actualLast := c.RemoveLast(); actualLast != entries[0] {
+		t.Fatal("unexpected item at end of embedded list")
+	}
+	if actualFirst := c.RemoveFirst(); actualFirst != entries[6] {
+		t.Fatal("unexpected item at front of embedded list")
+	}
+
+	other := embedded.NewCircularList[circularListEntry](circularListEntryLinkField)
+	other.InsertLast(entries[0])
+	if c.IsContained(entries[0]) || !other.IsContained(entries[0]) {
+		t.Fatal("item reported in the wrong list")
+	}
+
+	c.RemoveAll()
+	if !c.IsEmpty() || c.Count() != 0 {
+		t.Fatal("embedded list is not empty after RemoveAll")
+	}
+	if entries[1].link.IsLinked() {
+		t.Fatal("item is still linked after RemoveAll")
+	}
+}
+
+func BenchmarkEmbeddedCircularList_InsertLast(b *testing.B) {
+	list := embedded.NewCircularList[circularListEntry](circularListEntryLinkField)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		list.InsertLast(&circularListEntry{data: i})
+	}
+}
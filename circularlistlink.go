@@ -0,0 +1,46 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// CircularListLink is a link to the circular list container. Its neighbors are
+// other links rather than items, so it can be unlinked without the list.
+type CircularListLink[T any] struct {
+	prev *CircularListLink[T]
+	next *CircularListLink[T]
+}
+
+// Unlink removes the item holding this link from whichever circular list it is
+// in. Unlinking an item which is not in a list does nothing.
+func (l *CircularListLink[T]) Unlink() {
+	if l.next == nil {
+		return
+	}
+	l.prev.next = l.next
+	l.next.prev = l.prev
+	l.prev = nil
+	l.next = nil
+}
+
+// IsLinked reports whether the item holding this link is in a circular list.
+func (l *CircularListLink[T]) IsLinked() bool {
+	return l.next != nil
+}
+
+func (l *CircularListLink[T]) insertAfter(prev *CircularListLink[T]) {
+	l.prev = prev
+	l.next = prev.next
+	prev.next.prev = l
+	prev.next = l
+}
+
+func (l *CircularListLink[T]) getItem(linkFieldOfs uintptr) *T {
+	u := unsafe.Add(unsafe.Pointer(l), (^linkFieldOfs)+1)
+	return (*T)(u)
+}
+
+func getCircularListLink[T any](obj *T, linkFieldOfs uintptr) *CircularListLink[T] {
+	u := unsafe.Add(unsafe.Pointer(obj), linkFieldOfs)
+	return (*CircularListLink[T])(u)
+}
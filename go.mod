@@ -1,5 +1,5 @@
 module github.com/heucuva/go-embedded-container
 
-go 1.18
+go 1.19
 
 require golang.org/x/exp v0.0.0-20220316213622-354416b42cae
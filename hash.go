@@ -1,10 +1,17 @@
 package embedded
 
 import (
+	"time"
+
 	"github.com/heucuva/go-embedded-container/internal/array"
 )
 
 // This is a hash table container - it allows for fast lookup via a hash value.
+// A dynamic table grows as items are inserted and shrinks back once few of them
+// remain, which reorders the table - so items must not be removed during a
+// walk with WalkFirst and WalkNext. An incremental dynamic table spreads the
+// work of resizing over the inserts and removals which follow it, rather than
+// rehashing every item at once.
 // This cointainer does not take ownership of its contents, so the application
 // must remove items manually.
 
@@ -27,35 +34,106 @@ type Hash[T any] interface {
 	WalkNext(prevResult *T) *T
 }
 
-func NewHashStatic[T any](linkField uintptr, tableSize int) Hash[T] {
-	return &embeddedHash[T]{
+func NewHashStatic[T any](linkField uintptr, tableSize int, opts ...HashOption) Hash[T] {
+	o := makeHashOptions(opts)
+	o.checkUnkeyed()
+	return newHashStatic[T](linkField, tableSize, o)
+}
+
+func NewHashDynamic[T any](linkField uintptr, opts ...HashOption) Hash[T] {
+	o := makeHashOptions(opts)
+	o.checkUnkeyed()
+	return newHashDynamic[T](linkField, o)
+}
+
+// NewHashDynamicIncremental creates a hash with a dynamic table size which
+// resizes incrementally: the old table is kept alongside the new one after a
+// resize, and each following insert or removal migrates a few of its buckets.
+// It is NewHashDynamic with WithIncrementalResize.
+func NewHashDynamicIncremental[T any](linkField uintptr) Hash[T] {
+	return NewHashDynamic[T](linkField, WithIncrementalResize())
+}
+
+// NewHashDynamicWithPolicy creates a hash with a dynamic table size, which is
+// sized according to policy. It is NewHashDynamic with WithHashPolicy.
+func NewHashDynamicWithPolicy[T any](linkField uintptr, policy HashTablePolicy) Hash[T] {
+	return NewHashDynamic[T](linkField, WithHashPolicy(policy))
+}
+
+func newHashStatic[T any](linkField uintptr, tableSize int, o hashOptions) *embeddedHash[T] {
+	o.checkStatic()
+	h := &embeddedHash[T]{
 		linkField: linkField,
-		table:     array.NewStaticArray[*T](tableSize),
 	}
+	h.table = array.NewStaticArray(tableSize, h.onResize)
+	return h
 }
 
-func NewHashDynamic[T any](linkField uintptr) Hash[T] {
+func newHashDynamic[T any](linkField uintptr, o hashOptions) *embeddedHash[T] {
 	h := &embeddedHash[T]{
-		linkField: linkField,
+		linkField:   linkField,
+		policy:      o.policy,
+		incremental: o.incremental,
 	}
-	h.table = array.NewDynamicArray(minDynamicHashSize, h.onResize)
+	h.table = array.NewDynamicArray(o.policy.arrayPolicy(), h.onResize)
 	return h
 }
 
 const (
 	minDynamicHashSize = 8
+	// incrementalHashMigrateBuckets is the number of old buckets migrated by
+	// each insert or removal during an incremental resize.
+	incrementalHashMigrateBuckets = 8
 )
 
 type embeddedHash[T any] struct {
 	entryCount int
 	linkField  uintptr
 	table      array.Array[*T]
+	policy     HashTablePolicy
+
+	// tableUsed counts the non-empty buckets of both the current and the old
+	// table.
+	tableUsed    int
+	resizeCount  int
+	resizeTime   time.Duration
+	migrateSteps int
+	reseedCount  int
+	reseedTime   time.Duration
+
+	// maxChainLength is the number of colliding entries above the expected
+	// chain length at which an insert calls onLongChain, if set.
+	maxChainLength int
+	onLongChain    func()
+	// rehash recomputes the hash value of an entry while the table is being
+	// rebuilt by rehashAll.
+	rehash func(obj *T) HashedKeyValue
+
+	// old holds the buckets of the previous table during an incremental
+	// resize. Buckets before migrateSpot have been migrated, as has any
+	// other bucket left empty; all the entries of a hash value are in the
+	// old bucket for it as long as that is not empty, otherwise they are in
+	// the current table.
+	incremental bool
+	old         []*T
+	migrateSpot int
 }
 
 func (c *embeddedHash[T]) getLink(obj *T) *HashLink[T] {
 	return getHashLink(obj, c.linkField)
 }
 
+// newEmpty creates an empty hash with the same kind of table.
+func (c *embeddedHash[T]) newEmpty() *embeddedHash[T] {
+	if c.table.IsStatic() {
+		return newHashStatic[T](c.linkField, c.table.Size(), hashOptions{})
+	}
+	return newHashDynamic[T](c.linkField, hashOptions{
+		policy:      c.policy,
+		incremental: c.incremental,
+	})
+}
+
 func (c *embeddedHash[T]) calcSpot(hashValue HashedKeyValue) int {
 	return c.calcSpotForSize(hashValue, c.table.Size())
 }
@@ -64,20 +142,122 @@ func (c *embeddedHash[T]) calcSpotForSize(hashValue HashedKeyValue, tableSize in
 	return int(hashValue % HashedKeyValue(tableSize))
 }
 
+// isInOld reports whether the entries of hashValue are in the old table.
+func (c *embeddedHash[T]) isInOld(hashValue HashedKeyValue) bool {
+	return c.old != nil && c.old[c.calcSpotForSize(hashValue, len(c.old))] != nil
+}
+
+// tableOf returns the table holding the entries of hashValue.
+func (c *embeddedHash[T]) tableOf(hashValue HashedKeyValue) []*T {
+	if c.isInOld(hashValue) {
+		return c.old
+	}
+	return c.table.Slice()
+}
+
+// migrate moves the next few buckets of the old table into the current one,
+// along with the bucket holding the entries of hashValue. It is counted rather
+// than timed, as it runs on every insert and removal during the migration.
+func (c *embeddedHash[T]) migrate(hashValue HashedKeyValue) {
+	c.migrateSteps++
+
+	table := c.table.Slice()
+	for i := 0; i < incrementalHashMigrateBuckets && c.migrateSpot < len(c.old); i++ {
+		c.moveBucket(c.old[c.migrateSpot], table)
+		c.old[c.migrateSpot] = nil
+		c.migrateSpot++
+	}
+	if spot := c.calcSpotForSize(hashValue, len(c.old)); c.old[spot] != nil {
+		c.moveBucket(c.old[spot], table)
+		c.old[spot] = nil
+	}
+	if c.migrateSpot == len(c.old) {
+		c.old = nil
+	}
+}
+
+// finishMigration moves every bucket left in the old table into dest.
+func (c *embeddedHash[T]) finishMigration(dest []*T) {
+	for spot := c.migrateSpot; spot < len(c.old); spot++ {
+		c.moveBucket(c.old[spot], dest)
+	}
+	c.old = nil
+}
+
 func (c *embeddedHash[T]) Insert(hashValue HashedKeyValue, obj *T) *T {
 	if !c.table.IsStatic() {
 		c.Reserve(c.entryCount + 1)
 	}
+	if c.old != nil {
+		c.migrate(hashValue)
+	}
 	spot := c.calcSpot(hashValue)
 	entryLink := c.getLink(obj)
 	entryLink.hashValue = hashValue
+	entryLink.owner = c
 	entryLink.hashNext = c.table.Slice()[spot]
+	if entryLink.hashNext == nil {
+		c.tableUsed++
+	}
 	c.table.Slice()[spot] = obj
 	c.entryCount++
+	if c.onLongChain != nil && (entryLink.hashNext == nil || c.getLink(entryLink.hashNext).hashValue != hashValue) {
+		// an entry joining a run of its own hash value cannot lengthen the chain
+		if limit := c.maxChainLength + 2*c.entryCount/c.table.Size(); c.chainLength(spot, limit) > limit {
+			c.onLongChain()
+		}
+	}
 	return obj
 }
 
+// chainLength returns the number of contiguous runs of equal hash values in
+// the chain at spot, counting no further than just past limit. A run of entries
+// sharing a hash value (such as items with duplicate keys) is counted once, as
+// no choice of hash function could separate them; entries with the same hash
+// value in separate runs are counted once per run.
+func (c *embeddedHash[T]) chainLength(spot int, limit int) int {
+	var length int
+	var prevHashValue HashedKeyValue
+	for cur := c.table.Slice()[spot]; cur != nil && length <= limit; {
+		curLink := c.getLink(cur)
+		if length == 0 || curLink.hashValue != prevHashValue {
+			length++
+		}
+		prevHashValue = curLink.hashValue
+		cur = curLink.hashNext
+	}
+	return length
+}
+
+// rehashAll rebuilds the table in place, replacing the hash value of every
+// entry with the one returned by rehash.
+func (c *embeddedHash[T]) rehashAll(rehash func(obj *T) HashedKeyValue) {
+	start := time.Now()
+	defer func() {
+		c.reseedCount++
+		c.reseedTime += time.Since(start)
+	}()
+
+	src := c.table.Slice()
+	if c.old != nil {
+		c.finishMigration(src)
+	}
+	dest := make([]*T, len(src))
+	c.rehash = rehash
+	for _, current := range src {
+		c.moveBucket(current, dest)
+	}
+	c.rehash = nil
+	copy(src, dest)
+}
+
 func (c *embeddedHash[T]) Remove(obj *T) *T {
+	if c.getLink(obj).owner != c {
+		return nil
+	}
+	if c.old != nil {
+		c.migrate(c.getLink(obj).hashValue)
+	}
 	spot := c.calcSpot(c.getLink(obj).hashValue)
 	cur := c.table.Slice()[spot]
 	prev := &c.table.Slice()[spot]
@@ -86,9 +266,18 @@ func (c *embeddedHash[T]) Remove(obj *T) *T {
 		entryLink := c.getLink(cur)
 		if cur == obj {
 			*prev = entryLink.hashNext
+			if c.table.Slice()[spot] == nil {
+				c.tableUsed--
+			}
 			entryLink.hashNext = nil
 			entryLink.hashValue = 0
+			entryLink.owner = nil
 			c.entryCount--
+			if c.old == nil {
+				// a shrink waits for the migration to finish, rather than
+				// finishing it at once
+				c.table.Shrink(c.entryCount)
+			}
 			return cur
 		}
 		prev = &entryLink.hashNext
@@ -110,6 +299,24 @@ func (c *embeddedHash[T]) Reserve(count int) {
 	}
 }
 
+// Compact shrinks a dynamic table to the smallest size fitting its
+// contents; a static table is left as it is.
+func (c *embeddedHash[T]) Compact() {
+	c.table.Compact(c.entryCount)
+}
+
+// Resize rehashes the contents into a table of tableSize buckets. A dynamic
+// table adjusts the size as its policy does for any other resize: rounded up
+// to a power of 2 unless ExactSize is set, then kept between MinSize and
+// MaxSize. Dynamic tables still grow and shrink with their contents
+// afterwards.
+func (c *embeddedHash[T]) Resize(tableSize int) {
+	if tableSize <= 0 {
+		panic("hash table size must be positive")
+	}
+	c.table.Resize(tableSize)
+}
+
 func (c *embeddedHash[T]) GetKey(obj *T) HashedKeyValue {
 	return c.getLink(obj).hashValue
 }
@@ -123,17 +330,46 @@ func (c *embeddedHash[T]) GetTableSize() int {
 }
 
 func (c *embeddedHash[T]) GetTableUsed() int {
-	if c.entryCount <= 1 {
-		return c.entryCount
+	return c.tableUsed
+}
+
+// Stats reports the state of the table. The item and bucket counts are
+// kept up to date as items are inserted and removed, but the chain length
+// histogram is gathered by walking every bucket and chain, so Stats is
+// O(table size + items) and is meant for diagnostics rather than hot paths.
+func (c *embeddedHash[T]) Stats() HashTableStats {
+	stats := HashTableStats{
+		Count:        c.entryCount,
+		TableSize:    c.table.Size() + len(c.old),
+		TableUsed:    c.tableUsed,
+		ResizeCount:  c.resizeCount,
+		ResizeTime:   c.resizeTime,
+		MigrateSteps: c.migrateSteps,
+		ReseedCount:  c.reseedCount,
+		ReseedTime:   c.reseedTime,
+	}
+	if stats.TableSize != 0 {
+		stats.LoadFactor = float64(stats.Count) / float64(stats.TableSize)
 	}
 
-	var tableUsed int
-	for _, entry := range c.table.Slice() {
-		if entry != nil {
-			tableUsed++
+	stats.ChainLengths = append(stats.ChainLengths, stats.TableSize-stats.TableUsed)
+	for _, table := range [][]*T{c.table.Slice(), c.old} {
+		for _, cur := range table {
+			var length int
+			for ; cur != nil; cur = c.getLink(cur).hashNext {
+				length++
+			}
+			if length == 0 {
+				continue
+			}
+			for len(stats.ChainLengths) <= length {
+				stats.ChainLengths = append(stats.ChainLengths, 0)
+			}
+			stats.ChainLengths[length]++
 		}
 	}
-	return tableUsed
+	stats.MaxChainLength = len(stats.ChainLengths) - 1
+	return stats
 }
 
 func (c *embeddedHash[T]) IsEmpty() bool {
@@ -141,8 +377,8 @@ func (c *embeddedHash[T]) IsEmpty() bool {
 }
 
 func (c *embeddedHash[T]) FindFirst(hashValue HashedKeyValue) *T {
-	spot := c.calcSpot(hashValue)
-	entry := c.table.Slice()[spot]
+	table := c.tableOf(hashValue)
+	entry := table[c.calcSpotForSize(hashValue, len(table))]
 	for entry != nil {
 		entryLink := c.getLink(entry)
 		if entryLink.hashValue == hashValue {
@@ -173,85 +409,127 @@ func (c *embeddedHash[T]) WalkFirst() *T {
 		return nil
 	}
 
-	for _, entry := range c.table.Slice() {
-		if entry != nil {
-			return entry
-		}
-	}
-	return nil
+	return c.walkFrom(c.table.Slice(), 0, false)
 }
 
 func (c *embeddedHash[T]) WalkNext(prevResult *T) *T {
 	entry := prevResult
 	entryLink := c.getLink(entry)
-	spot := c.calcSpot(entryLink.hashValue)
+	inOld := c.isInOld(entryLink.hashValue)
+	table := c.tableOf(entryLink.hashValue)
+	spot := c.calcSpotForSize(entryLink.hashValue, len(table))
 	entry = entryLink.hashNext
 	if entry != nil {
 		return entry
 	}
+	return c.walkFrom(table, spot+1, inOld)
+}
 
-	for spot++; spot < c.table.Size(); spot++ {
-		entry = c.table.Slice()[spot]
-		if entry != nil {
+// walkFrom returns the first entry in table from spot on. A walk of the current
+// table continues into the old table, if there is one.
+func (c *embeddedHash[T]) walkFrom(table []*T, spot int, inOld bool) *T {
+	for ; spot < len(table); spot++ {
+		if entry := table[spot]; entry != nil {
 			return entry
 		}
 	}
+	if !inOld && c.old != nil {
+		return c.walkFrom(c.old, 0, true)
+	}
 	return nil
 }
 
 func (c *embeddedHash[T]) RemoveAll() {
-	cur := c.WalkFirst()
-	for cur != nil {
-		next := c.WalkNext(cur)
-		c.Remove(cur)
-		cur = next
+	c.unlinkAll(c.table.Slice())
+	c.unlinkAll(c.old)
+	c.old = nil
+	c.entryCount = 0
+	c.tableUsed = 0
+	c.table.Shrink(0)
+}
+
+func (c *embeddedHash[T]) unlinkAll(table []*T) {
+	for spot, cur := range table {
+		for cur != nil {
+			curLink := c.getLink(cur)
+			next := curLink.hashNext
+			curLink.hashNext = nil
+			curLink.hashValue = 0
+			curLink.owner = nil
+			cur = next
+		}
+		table[spot] = nil
 	}
 }
 
 func (c *embeddedHash[T]) IsContained(cur *T) bool {
-	curLink := c.getLink(cur)
-	if curLink.hashValue != 0 || curLink.hashNext != nil {
-		return true
+	return c.getLink(cur).owner == c
+}
+
+func (c *embeddedHash[T]) onResize(dest, src []*T) {
+	if len(src) == 0 {
+		// the initial allocation of the table
+		return
 	}
 
-	walk := c.table.Slice()[0]
-	for walk != nil {
-		if walk == cur {
-			return true
+	start := time.Now()
+	defer func() {
+		c.resizeCount++
+		c.resizeTime += time.Since(start)
+	}()
+
+	if c.incremental {
+		if c.old != nil {
+			c.finishMigration(src)
+		}
+		if c.entryCount != 0 {
+			c.old = src
+			c.migrateSpot = 0
 		}
-		walk = c.getLink(walk).hashNext
+		return
 	}
-	return false
-}
 
-func (c *embeddedHash[T]) onResize(dest, src []*T) {
 	if c.entryCount == 0 {
 		return
 	}
 
-	dynamicSize := len(dest)
 	for _, current := range src {
-		if current == nil {
-			continue
-		}
+		c.moveBucket(current, dest)
+	}
+}
 
-		var tempBucketRoot *T
-		for current != nil {
-			currentLink := c.getLink(current)
-			next := currentLink.hashNext
-			currentLink.hashNext = tempBucketRoot
-			tempBucketRoot = current
-			current = next
-		}
+// moveBucket moves the chain of entries starting with current into dest,
+// keeping the order of entries landing in the same bucket.
+func (c *embeddedHash[T]) moveBucket(current *T, dest []*T) {
+	if current == nil {
+		return
+	}
+	// the source bucket is left empty
+	c.tableUsed--
+
+	var tempBucketRoot *T
+	for current != nil {
+		currentLink := c.getLink(current)
+		next := currentLink.hashNext
+		currentLink.hashNext = tempBucketRoot
+		tempBucketRoot = current
+		current = next
+	}
 
-		current = tempBucketRoot
-		for current != nil {
-			currentLink := c.getLink(current)
-			next := currentLink.hashNext
-			spot := c.calcSpotForSize(currentLink.hashValue, dynamicSize)
-			currentLink.hashNext = dest[spot]
-			dest[spot] = current
-			current = next
+	dynamicSize := len(dest)
+	current = tempBucketRoot
+	for current != nil {
+		currentLink := c.getLink(current)
+		next := currentLink.hashNext
+		if c.rehash != nil {
+			currentLink.hashValue = c.rehash(current)
+		}
+		spot := c.calcSpotForSize(currentLink.hashValue, dynamicSize)
+		currentLink.hashNext = dest[spot]
+		if currentLink.hashNext == nil {
+			c.tableUsed++
 		}
+		dest[spot] = current
+		current = next
 	}
 }
@@ -1,6 +1,7 @@
 package embedded_test
 
 import (
+	"math/rand"
 	"testing"
 	"unsafe"
 
@@ -17,7 +18,7 @@ var hashEntryLinkField = unsafe.Offsetof(hashEntry{}.link)
 func TestEmbeddedHashStatic(t *testing.T) {
 	const staticSize = 1000
 	const testSize = int(staticSize * 5.5)
-	const expectedTableUsed = 996
+	const expectedTableUsed = 1000
 	const removeTarget = (testSize / 2) - 1
 	c := embedded.NewHashStatic[hashEntry](hashEntryLinkField, staticSize)
 	testEmbeddedHash(t, c, testSize, expectedTableUsed, staticSize, removeTarget)
@@ -26,7 +27,7 @@ func TestEmbeddedHashStatic(t *testing.T) {
 func TestEmbeddedHashStaticReserve(t *testing.T) {
 	const staticSize = 1000
 	const testSize = int(staticSize * 5.5)
-	const expectedTableUsed = 996
+	const expectedTableUsed = 1000
 	const removeTarget = (testSize / 2) - 1
 	c := embedded.NewHashStatic[hashEntry](hashEntryLinkField, staticSize)
 	defer func() {
@@ -41,13 +42,321 @@ func TestEmbeddedHashStaticReserve(t *testing.T) {
 
 func TestEmbeddedHashDynamic(t *testing.T) {
 	const testSize = 5500
-	const expectedTableUsed = 4116
+	const expectedTableUsed = 3976
 	const expectedTableSize = 8192 // next power of 2 over 5500
 	const removeTarget = (testSize / 2) - 1
 	c := embedded.NewHashDynamic[hashEntry](hashEntryLinkField)
 	testEmbeddedHash(t, c, testSize, expectedTableUsed, expectedTableSize, removeTarget)
 }
 
+func TestEmbeddedHashDynamicIncremental(t *testing.T) {
+	const testSize = 5500
+	const expectedTableUsed = 3976
+	const expectedTableSize = 8192 // next power of 2 over 5500
+	const removeTarget = (testSize / 2) - 1
+	c := embedded.NewHashDynamicIncremental[hashEntry](hashEntryLinkField)
+	testEmbeddedHash(t, c, testSize, expectedTableUsed, expectedTableSize, removeTarget)
+}
+
+func TestEmbeddedHashIncrementalMigration(t *testing.T) {
+	const testSize = 20000
+	entries := make([]hashEntry, testSize)
+	contained := make(map[*hashEntry]bool)
+	c := embedded.NewHashDynamicIncremental[hashEntry](hashEntryLinkField)
+	r := rand.New(rand.NewSource(1))
+
+	check := func() {
+		t.Helper()
+		if actualCount := c.Count(); actualCount != len(contained) {
+			t.Fatalf("unexpected hash count (actual %d != expected %d)", actualCount, len(contained))
+		}
+		walked := 0
+		for walk := c.WalkFirst(); walk != nil; walk = c.WalkNext(walk) {
+			if !contained[walk] {
+				t.Fatal("walk reached an item which is not contained")
+			}
+			walked++
+		}
+		if walked != len(contained) {
+			t.Fatalf("unexpected walk count (actual %d != expected %d)", walked, len(contained))
+		}
+		testEmbeddedHashStats(t, c.Stats())
+	}
+
+	for i := 0; i < 4*testSize; i++ {
+		entry := &entries[r.Intn(testSize)]
+		// few distinct keys, so many items share a hash value
+		key := embedded.HashKey(r.Intn(testSize / 4))
+		if contained[entry] {
+			if c.Remove(entry) != entry {
+				t.Fatal("contained item could not be removed")
+			}
+			delete(contained, entry)
+		} else {
+			c.Insert(key, entry)
+			contained[entry] = true
+		}
+
+		found := false
+		for cur := c.FindFirst(key); cur != nil; cur = c.FindNext(cur) {
+			if c.GetKey(cur) != key {
+				t.Fatal("found an item with a mismatched key")
+			}
+			found = found || cur == entry
+		}
+		if found != contained[entry] || c.IsContained(entry) != contained[entry] {
+			t.Fatal("unexpected containment of inserted or removed item")
+		}
+		if i%997 == 0 {
+			check()
+		}
+		if i%1009 == 0 {
+			// resize again, possibly in the middle of a migration
+			c.Resize(1 << (8 + r.Intn(8)))
+		}
+	}
+	check()
+}
+
+func TestEmbeddedHashIncrementalShrink(t *testing.T) {
+	const testSize = 100
+	entries := make([]hashEntry, testSize)
+	c := embedded.NewHashDynamicIncremental[hashEntry](hashEntryLinkField)
+	for i := range entries {
+		c.Insert(embedded.HashKey(i), &entries[i])
+	}
+
+	// the resize leaves the table far larger than its contents need, but the
+	// removals must not shrink it while they are migrating it
+	c.Resize(4096)
+	resizeCount := c.Stats().ResizeCount
+	for i := 0; i < 4; i++ {
+		c.Remove(&entries[i])
+		if actualTableSize := c.GetTableSize(); actualTableSize != 4096 {
+			t.Fatalf("unexpected table size during migration (actual %d != expected %d)", actualTableSize, 4096)
+		}
+	}
+	stats := c.Stats()
+	if stats.ResizeCount != resizeCount || stats.MigrateSteps == 0 {
+		t.Fatal("migration was not left to continue incrementally")
+	}
+
+	for i := 4; i < testSize; i++ {
+		c.Remove(&entries[i])
+	}
+	if actualTableSize := c.GetTableSize(); actualTableSize >= 4096 {
+		t.Fatal("table was not shrunk once the migration finished")
+	}
+}
+
+func TestEmbeddedHashDynamicWithPolicy(t *testing.T) {
+	const testSize = 1000
+	entries := make([]hashEntry, testSize)
+	c := embedded.NewHashDynamicWithPolicy[hashEntry](hashEntryLinkField, embedded.HashTablePolicy{
+		LoadFactor:   1,
+		GrowthFactor: 1.5,
+		MinSize:      100,
+		ExactSize:    true,
+	})
+	if actualTableSize := c.GetTableSize(); actualTableSize != 100 {
+		t.Fatalf("unexpected table size (actual %d != expected %d)", actualTableSize, 100)
+	}
+	for i := range entries {
+		entries[i].data = i
+		c.Insert(embedded.HashKey(i), &entries[i])
+	}
+	// grown by half each time past 100: 150, 225, 337, 505, 757 then 1135
+	if actualTableSize := c.GetTableSize(); actualTableSize != 1135 {
+		t.Fatalf("unexpected table size (actual %d != expected %d)", actualTableSize, 1135)
+	}
+	testEmbeddedHashContents(t, c, entries)
+
+	c.RemoveAll()
+	if actualTableSize := c.GetTableSize(); actualTableSize != 100 {
+		t.Fatalf("unexpected table size after removing all items (actual %d != expected %d)", actualTableSize, 100)
+	}
+
+	for _, policy := range []embedded.HashTablePolicy{
+		{LoadFactor: -1},
+		{GrowthFactor: 1},
+		{MinSize: -1},
+		{MinSize: 100, MaxSize: 10},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected a panic creating a hash with policy %+v", policy)
+				}
+			}()
+			embedded.NewHashDynamicWithPolicy[hashEntry](hashEntryLinkField, policy)
+		}()
+	}
+}
+
+func TestEmbeddedHashContainment(t *testing.T) {
+	a := embedded.NewHashStatic[hashEntry](hashEntryLinkField, 10)
+	b := embedded.NewHashStatic[hashEntry](hashEntryLinkField, 10)
+	zero := &hashEntry{data: 0}
+	other := &hashEntry{data: 1}
+	a.Insert(0, zero)
+	a.Insert(0, &hashEntry{data: 2})
+	a.Insert(13, other)
+
+	if !a.IsContained(zero) || !a.IsContained(other) {
+		t.Fatal("embedded hash reports that contained item is not present")
+	}
+	if b.IsContained(zero) || b.IsContained(other) {
+		t.Fatal("embedded hash reports that an item of another hash is present")
+	}
+	if b.Remove(zero) != nil || b.Remove(other) != nil {
+		t.Fatal("embedded hash removed an item of another hash")
+	}
+	if a.FindFirst(0) == nil || a.FindFirst(13) != other || a.Count() != 3 {
+		t.Fatal("removal from another hash changed the contents")
+	}
+
+	if a.Remove(zero) != zero {
+		t.Fatal("contained item could not be removed")
+	}
+	if a.IsContained(zero) || a.Remove(zero) != nil {
+		t.Fatal("embedded hash reports that removed item is present")
+	}
+	if a.Count() != 2 {
+		t.Fatalf("unexpected hash count (actual %d != expected %d)", a.Count(), 2)
+	}
+
+	a.RemoveAll()
+	if a.IsContained(other) {
+		t.Fatal("embedded hash reports that removed item is present")
+	}
+}
+
+func TestEmbeddedHashStats(t *testing.T) {
+	const staticSize = 1000
+	const testSize = int(staticSize * 5.5)
+	s := embedded.NewHashStatic[hashEntry](hashEntryLinkField, staticSize)
+	d := embedded.NewHashDynamic[hashEntry](hashEntryLinkField)
+	for i := 0; i < testSize; i++ {
+		s.Insert(embedded.HashKey(i), &hashEntry{data: i})
+		d.Insert(embedded.HashKey(i), &hashEntry{data: i})
+	}
+
+	stats := s.Stats()
+	testEmbeddedHashStats(t, stats)
+	if stats.Count != testSize || stats.TableSize != staticSize || stats.LoadFactor != 5.5 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats.ResizeCount != 0 {
+		t.Fatalf("unexpected resize count (actual %d != expected %d)", stats.ResizeCount, 0)
+	}
+
+	stats = d.Stats()
+	testEmbeddedHashStats(t, stats)
+	if stats.Count != testSize || stats.TableSize != 8192 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	// doubled from 16 up to 8192
+	if stats.ResizeCount != 9 {
+		t.Fatalf("unexpected resize count (actual %d != expected %d)", stats.ResizeCount, 9)
+	}
+}
+
+// testEmbeddedHashStats checks that the stats kept as items are inserted and
+// removed agree with the chain length histogram gathered by walking.
+func testEmbeddedHashStats(t *testing.T, stats embedded.HashTableStats) {
+	t.Helper()
+	var buckets, items int
+	for length, count := range stats.ChainLengths {
+		buckets += count
+		items += length * count
+	}
+	if buckets != stats.TableSize {
+		t.Fatalf("unexpected bucket count in histogram (actual %d != expected %d)", buckets, stats.TableSize)
+	}
+	if items != stats.Count {
+		t.Fatalf("unexpected item count in histogram (actual %d != expected %d)", items, stats.Count)
+	}
+	if stats.TableUsed != stats.TableSize-stats.ChainLengths[0] {
+		t.Fatalf("unexpected table used size (actual %d != expected %d)", stats.TableUsed, stats.TableSize-stats.ChainLengths[0])
+	}
+	if stats.MaxChainLength != len(stats.ChainLengths)-1 || (stats.MaxChainLength != 0 && stats.ChainLengths[stats.MaxChainLength] == 0) {
+		t.Fatalf("unexpected max chain length %d", stats.MaxChainLength)
+	}
+}
+
+func TestEmbeddedHashResize(t *testing.T) {
+	const testSize = 10000
+	const keepSize = 100
+	entries := make([]hashEntry, testSize)
+	c := embedded.NewHashDynamic[hashEntry](hashEntryLinkField)
+	for i := range entries {
+		entries[i].data = i
+		c.Insert(embedded.HashKey(i), &entries[i])
+	}
+	if actualTableSize := c.GetTableSize(); actualTableSize != 16384 {
+		t.Fatalf("unexpected table size (actual %d != expected %d)", actualTableSize, 16384)
+	}
+
+	for i := keepSize; i < testSize; i++ {
+		c.Remove(&entries[i])
+	}
+	if actualTableSize := c.GetTableSize(); actualTableSize != 256 {
+		t.Fatalf("unexpected table size after removal (actual %d != expected %d)", actualTableSize, 256)
+	}
+	c.Compact()
+	if actualTableSize := c.GetTableSize(); actualTableSize != 128 {
+		t.Fatalf("unexpected table size after compaction (actual %d != expected %d)", actualTableSize, 128)
+	}
+	testEmbeddedHashContents(t, c, entries[:keepSize])
+
+	c.Resize(1000)
+	if actualTableSize := c.GetTableSize(); actualTableSize != 1024 {
+		t.Fatalf("unexpected table size after resize (actual %d != expected %d)", actualTableSize, 1024)
+	}
+	testEmbeddedHashContents(t, c, entries[:keepSize])
+	c.RemoveAll()
+	if actualTableSize := c.GetTableSize(); actualTableSize != 8 {
+		t.Fatalf("unexpected table size after removing all items (actual %d != expected %d)", actualTableSize, 8)
+	}
+
+	s := embedded.NewHashStatic[hashEntry](hashEntryLinkField, 1000)
+	for i := 0; i < keepSize; i++ {
+		s.Insert(embedded.HashKey(i), &entries[keepSize+i])
+	}
+	s.Compact()
+	if actualTableSize := s.GetTableSize(); actualTableSize != 1000 {
+		t.Fatalf("unexpected table size after compaction (actual %d != expected %d)", actualTableSize, 1000)
+	}
+	s.Resize(10)
+	if actualTableSize := s.GetTableSize(); actualTableSize != 10 {
+		t.Fatalf("unexpected table size after resize (actual %d != expected %d)", actualTableSize, 10)
+	}
+	testEmbeddedHashContents(t, s, entries[keepSize:2*keepSize])
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic resizing to an empty table")
+		}
+	}()
+	s.Resize(0)
+}
+
+func testEmbeddedHashContents(t *testing.T, c embedded.Hash[hashEntry], entries []hashEntry) {
+	t.Helper()
+	if actualCount := c.Count(); actualCount != len(entries) {
+		t.Fatalf("unexpected hash count (actual %d != expected %d)", actualCount, len(entries))
+	}
+	for i := range entries {
+		found := false
+		for cur := c.FindFirst(c.GetKey(&entries[i])); cur != nil; cur = c.FindNext(cur) {
+			found = found || cur == &entries[i]
+		}
+		if !found {
+			t.Fatal("expected entry not found")
+		}
+	}
+}
+
 func BenchmarkEmbeddedHashStatic_Insert(b *testing.B) {
 	hash := embedded.NewHashStatic[hashEntry](hashEntryLinkField, b.N)
 	b.ReportAllocs()
@@ -57,6 +366,15 @@ func BenchmarkEmbeddedHashStatic_Insert(b *testing.B) {
 	}
 }
 
+func BenchmarkEmbeddedHashDynamicIncremental_Insert(b *testing.B) {
+	hash := embedded.NewHashDynamicIncremental[hashEntry](hashEntryLinkField)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		hkey := embedded.HashKey(i)
+		hash.Insert(hkey, &hashEntry{data: i})
+	}
+}
+
 func BenchmarkEmbeddedHashDynamic_Insert(b *testing.B) {
 	hash := embedded.NewHashDynamic[hashEntry](hashEntryLinkField)
 	b.ReportAllocs()
@@ -0,0 +1,158 @@
+package embedded
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"reflect"
+	"unsafe"
+)
+
+// Hasher computes the hash value of a key for the hashed map containers.
+type Hasher[TKey HashMapKeyType] func(key TKey) HashedKeyValue
+
+// hashKeyData returns the data of key consumed by the byte-oriented hashers:
+// the contents of a string key, otherwise the in-memory representation of the
+// key. A negative zero float key is normalized in place, so it hashes like
+// positive zero. The result aliases key, so it must not outlive it.
+func hashKeyData[TKey HashMapKeyType](key *TKey) string {
+	switch reflect.TypeOf((*TKey)(nil)).Elem().Kind() {
+	case reflect.String:
+		return *(*string)(unsafe.Pointer(key))
+	case reflect.Float32, reflect.Float64:
+		var zero TKey
+		if *key == zero {
+			*key = zero
+		}
+	}
+	b := unsafe.Slice((*byte)(unsafe.Pointer(key)), unsafe.Sizeof(*key))
+	return *(*string)(unsafe.Pointer(&b))
+}
+
+const (
+	fnv1aOffset64 = 14695981039346656037
+	fnv1aPrime64  = 1099511628211
+)
+
+// HashKeyFNV1a hashes key with 64-bit FNV-1a, which is fast for short keys.
+func HashKeyFNV1a[TKey HashMapKeyType](key TKey) HashedKeyValue {
+	data := hashKeyData(&key)
+	h := uint64(fnv1aOffset64)
+	for i := 0; i < len(data); i++ {
+		h ^= uint64(data[i])
+		h *= fnv1aPrime64
+	}
+	return HashedKeyValue(h)
+}
+
+const (
+	xxPrime64_1 = 11400714785074694791
+	xxPrime64_2 = 14029467366897019727
+	xxPrime64_3 = 1609587929392839161
+	xxPrime64_4 = 9650029242287828579
+	xxPrime64_5 = 2870177450012600261
+)
+
+// HashKeyXX hashes key with 64-bit xxHash (XXH64, seed 0), which distributes
+// well and is fast for long keys.
+func HashKeyXX[TKey HashMapKeyType](key TKey) HashedKeyValue {
+	data := hashKeyData(&key)
+	n := len(data)
+
+	var h uint64
+	if n >= 32 {
+		// the initial accumulators are prime1+prime2, prime2, 0 and -prime1
+		v1 := uint64(6983438078262162902)
+		v2 := uint64(xxPrime64_2)
+		v3 := uint64(0)
+		v4 := uint64(7046029288634856825)
+		for ; len(data) >= 32; data = data[32:] {
+			v1 = xxRound(v1, xxRead64(data))
+			v2 = xxRound(v2, xxRead64(data[8:]))
+			v3 = xxRound(v3, xxRead64(data[16:]))
+			v4 = xxRound(v4, xxRead64(data[24:]))
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) + bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxMergeRound(h, v1)
+		h = xxMergeRound(h, v2)
+		h = xxMergeRound(h, v3)
+		h = xxMergeRound(h, v4)
+	} else {
+		h = xxPrime64_5
+	}
+	h += uint64(n)
+
+	for ; len(data) >= 8; data = data[8:] {
+		h ^= xxRound(0, xxRead64(data))
+		h = bits.RotateLeft64(h, 27)*xxPrime64_1 + xxPrime64_4
+	}
+	if len(data) >= 4 {
+		h ^= uint64(xxRead32(data)) * xxPrime64_1
+		h = bits.RotateLeft64(h, 23)*xxPrime64_2 + xxPrime64_3
+		data = data[4:]
+	}
+	for i := 0; i < len(data); i++ {
+		h ^= uint64(data[i]) * xxPrime64_5
+		h = bits.RotateLeft64(h, 11) * xxPrime64_1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime64_2
+	h ^= h >> 29
+	h *= xxPrime64_3
+	h ^= h >> 32
+	return HashedKeyValue(h)
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime64_2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime64_1
+}
+
+func xxMergeRound(acc, val uint64) uint64 {
+	acc ^= xxRound(0, val)
+	return acc*xxPrime64_1 + xxPrime64_4
+}
+
+func xxRead64(data string) uint64 {
+	return uint64(data[0]) | uint64(data[1])<<8 | uint64(data[2])<<16 | uint64(data[3])<<24 |
+		uint64(data[4])<<32 | uint64(data[5])<<40 | uint64(data[6])<<48 | uint64(data[7])<<56
+}
+
+func xxRead32(data string) uint32 {
+	return uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16 | uint32(data[3])<<24
+}
+
+// NewHashKeyMaphash returns a hasher built on hash/maphash with a random seed,
+// so the hash values differ between hashers and between runs of the program.
+func NewHashKeyMaphash[TKey HashMapKeyType]() Hasher[TKey] {
+	return newSeededHasher[TKey]().hash
+}
+
+// seededHashMaxChainLength is the number of colliding entries above the
+// expected chain length at which a seeded container picks a new seed and
+// rehashes its contents.
+const seededHashMaxChainLength = 8
+
+// seededHasher hashes keys with hash/maphash under a seed which can be
+// replaced, for the seeded modes of the hashed map containers.
+type seededHasher[TKey HashMapKeyType] struct {
+	seed maphash.Seed
+}
+
+func newSeededHasher[TKey HashMapKeyType]() *seededHasher[TKey] {
+	return &seededHasher[TKey]{
+		seed: maphash.MakeSeed(),
+	}
+}
+
+func (s *seededHasher[TKey]) hash(key TKey) HashedKeyValue {
+	var h maphash.Hash
+	h.SetSeed(s.seed)
+	_, _ = h.WriteString(hashKeyData(&key))
+	return HashedKeyValue(h.Sum64())
+}
+
+func (s *seededHasher[TKey]) reseed() {
+	s.seed = maphash.MakeSeed()
+}
@@ -0,0 +1,108 @@
+package embedded_test
+
+import (
+	"testing"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+func TestHashKeyFNV1a(t *testing.T) {
+	for _, tc := range []struct {
+		key      string
+		expected embedded.HashedKeyValue
+	}{
+		{"", 0xcbf29ce484222325},
+		{"a", 0xaf63dc4c8601ec8c},
+		{"foobar", 0x85944171f73967e8},
+	} {
+		if actual := embedded.HashKeyFNV1a(tc.key); actual != tc.expected {
+			t.Fatalf("unexpected hash of %q (actual %x != expected %x)", tc.key, actual, tc.expected)
+		}
+	}
+}
+
+func TestHashKeyXX(t *testing.T) {
+	for _, tc := range []struct {
+		key      string
+		expected embedded.HashedKeyValue
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"abc", 0x44bc2cf5ad770999},
+		{"Nobody inspects the spammish repetition", 0xfbcea83c8a378bf1},
+	} {
+		if actual := embedded.HashKeyXX(tc.key); actual != tc.expected {
+			t.Fatalf("unexpected hash of %q (actual %x != expected %x)", tc.key, actual, tc.expected)
+		}
+	}
+}
+
+func TestHashKeyMaphash(t *testing.T) {
+	hasher := embedded.NewHashKeyMaphash[string]()
+	if hasher("key") != hasher("key") {
+		t.Fatal("hasher is not deterministic")
+	}
+	if hasher("key") == hasher("yek") {
+		t.Fatal("unexpected hash collision")
+	}
+}
+
+func TestEmbeddedHashMapWithHasher(t *testing.T) {
+	const testSize = 5500
+	for name, hasher := range map[string]embedded.Hasher[int]{
+		"FNV1a":   embedded.HashKeyFNV1a[int],
+		"XX":      embedded.HashKeyXX[int],
+		"Maphash": embedded.NewHashKeyMaphash[int](),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := embedded.NewHashMapDynamicWithHasher[int, hashMapEntry](hashMapEntryLinkField, hasher)
+			for i := 0; i < testSize; i++ {
+				c.Insert(i, &hashMapEntry{data: i})
+			}
+			for i := 0; i < testSize; i++ {
+				entry := c.FindFirst(i)
+				if entry == nil || entry.data != i {
+					t.Fatal("expected entry not found")
+				}
+				if i%2 == 0 {
+					c.Move(entry, testSize+i)
+				}
+			}
+			for i := 0; i < testSize; i += 2 {
+				if c.FindFirst(i) != nil {
+					t.Fatal("moved entry found by its old key")
+				}
+				c.RemoveAllByKey(testSize + i)
+			}
+			if actualCount := c.Count(); actualCount != testSize/2 {
+				t.Fatalf("unexpected hash count (actual %d != expected %d)", actualCount, testSize/2)
+			}
+
+			l := embedded.NewHashListMapStaticWithHasher[int, hashListMapEntry](hashListMapEntryLinkField, 100, hasher)
+			for i := 0; i < testSize; i++ {
+				l.InsertLast(i, &hashListMapEntry{data: i})
+			}
+			for i := 0; i < testSize; i++ {
+				if entry := l.FindFirst(i); entry == nil || entry.data != i {
+					t.Fatal("expected entry not found")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHashKey(b *testing.B) {
+	for name, hasher := range map[string]embedded.Hasher[string]{
+		"Default": embedded.HashKey[string],
+		"FNV1a":   embedded.HashKeyFNV1a[string],
+		"XX":      embedded.HashKeyXX[string],
+		"Maphash": embedded.NewHashKeyMaphash[string](),
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				hasher("a moderately long string key")
+			}
+		})
+	}
+}
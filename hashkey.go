@@ -1,8 +1,9 @@
 package embedded
 
 import (
-	"fmt"
-	"hash/fnv"
+	"math"
+	"reflect"
+	"unsafe"
 
 	"golang.org/x/exp/constraints"
 )
@@ -13,20 +14,103 @@ type HashMapKeyType interface {
 
 type HashedKeyValue uint64
 
-type hashKey[TKey HashMapKeyType] struct {
+// HashedKey is a key together with its hash value. A key which is looked up in
+// several hash maps or hash list maps can be hashed once into a HashedKey and
+// passed to their *Hashed methods, which then skip hashing it again.
+// NewHashedKey hashes with the default hasher, and the HashKey method of a
+// container hashes with the hasher of that container. The key records which
+// hasher produced it, and passing it to a container with another hasher
+// panics; containers created with the same WithHasher option value share a
+// hasher. Seeded containers hash with a seed of their own: they accept the
+// keys returned by their HashKey method as they are until they next reseed,
+// and hash any other key again, which saves nothing over the unhashed methods.
+type HashedKey[TKey HashMapKeyType] struct {
+	linkKey[TKey]
+	domain *hashDomain
+}
+
+// linkKey is the part of a HashedKey stored in the link of an item; the hasher
+// of the key is that of the container holding the item.
+type linkKey[TKey HashMapKeyType] struct {
 	value TKey
 	hash  HashedKeyValue
 }
 
-func newHashKey[TKey HashMapKeyType](key TKey) hashKey[TKey] {
-	return hashKey[TKey]{
-		value: key,
-		hash:  HashKey(key),
+// hashDomain identifies the hasher of a HashedKey. The default hasher is
+// identified by a nil domain.
+type hashDomain struct {
+	// the field keeps each domain at a distinct address
+	_ byte
+}
+
+// NewHashedKey hashes key with HashKey, the default hasher.
+func NewHashedKey[TKey HashMapKeyType](key TKey) HashedKey[TKey] {
+	return newHashedKey(key, HashKey[TKey], nil)
+}
+
+func newHashedKey[TKey HashMapKeyType](key TKey, hasher Hasher[TKey], domain *hashDomain) HashedKey[TKey] {
+	return HashedKey[TKey]{
+		linkKey: linkKey[TKey]{
+			value: key,
+			hash:  hasher(key),
+		},
+		domain: domain,
 	}
 }
 
+// Key returns the key which was hashed.
+func (k HashedKey[TKey]) Key() TKey {
+	return k.value
+}
+
+// Hash returns the hash value of the key.
+func (k HashedKey[TKey]) Hash() HashedKeyValue {
+	return k.hash
+}
+
+// HashKey is the default hasher of the hashed map containers. It dispatches on
+// the kind of the key without allocating: integers are mixed directly, floats
+// are normalized first (so -0 and +0 hash alike) and strings are hashed with
+// 64-bit FNV-1a.
 func HashKey[TKey HashMapKeyType](key TKey) HashedKeyValue {
-	h := fnv.New64()
-	_, _ = h.Write([]byte(fmt.Sprint(key)))
-	return HashedKeyValue(h.Sum64())
+	switch reflect.TypeOf((*TKey)(nil)).Elem().Kind() {
+	case reflect.String:
+		return HashKeyFNV1a(key)
+	case reflect.Float32:
+		f := *(*float32)(unsafe.Pointer(&key))
+		if f == 0 {
+			f = 0
+		}
+		return HashedKeyValue(mixHashKey(uint64(math.Float32bits(f))))
+	case reflect.Float64:
+		f := *(*float64)(unsafe.Pointer(&key))
+		if f == 0 {
+			f = 0
+		}
+		return HashedKeyValue(mixHashKey(math.Float64bits(f)))
+	}
+
+	var v uint64
+	switch unsafe.Sizeof(key) {
+	case 1:
+		v = uint64(*(*uint8)(unsafe.Pointer(&key)))
+	case 2:
+		v = uint64(*(*uint16)(unsafe.Pointer(&key)))
+	case 4:
+		v = uint64(*(*uint32)(unsafe.Pointer(&key)))
+	default:
+		v = *(*uint64)(unsafe.Pointer(&key))
+	}
+	return HashedKeyValue(mixHashKey(v))
+}
+
+// mixHashKey is the 64-bit finalizer of MurmurHash3, which spreads every input
+// bit across the whole result so consecutive integers land in distant buckets.
+func mixHashKey(v uint64) uint64 {
+	v ^= v >> 33
+	v *= 0xff51afd7ed558ccd
+	v ^= v >> 33
+	v *= 0xc4ceb9fe1a85ec53
+	v ^= v >> 33
+	return v
 }
@@ -0,0 +1,86 @@
+package embedded_test
+
+import (
+	"math"
+	"testing"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+func TestHashKeyFloatZero(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	if embedded.HashKey(negZero) != embedded.HashKey(0.0) {
+		t.Fatal("-0 and +0 hash differently")
+	}
+	if embedded.HashKey(float32(negZero)) != embedded.HashKey(float32(0)) {
+		t.Fatal("-0 and +0 hash differently")
+	}
+	if embedded.HashKeyXX(negZero) != embedded.HashKeyXX(0.0) {
+		t.Fatal("-0 and +0 hash differently")
+	}
+}
+
+func TestHashKeyKinds(t *testing.T) {
+	type name string
+	if embedded.HashKey(name("key")) != embedded.HashKey("key") {
+		t.Fatal("named string type hashes differently")
+	}
+	if embedded.HashKey(int8(-1)) == embedded.HashKey(int8(1)) {
+		t.Fatal("unexpected hash collision")
+	}
+	if embedded.HashKey(uint64(1)) == embedded.HashKey(uint64(1<<32)) {
+		t.Fatal("unexpected hash collision")
+	}
+}
+
+func TestHashKeyAllocs(t *testing.T) {
+	key := "a moderately long string key"
+	for name, fn := range map[string]func(){
+		"Int":     func() { embedded.HashKey(12345) },
+		"Uint8":   func() { embedded.HashKey(uint8(123)) },
+		"Float64": func() { embedded.HashKey(123.45) },
+		"String":  func() { embedded.HashKey(key) },
+	} {
+		if allocs := testing.AllocsPerRun(100, fn); allocs != 0 {
+			t.Fatalf("%s: unexpected allocations (actual %v != expected 0)", name, allocs)
+		}
+	}
+}
+
+func TestEmbeddedHashMapAllocs(t *testing.T) {
+	const testSize = 1000
+	c := embedded.NewHashMapStatic[int, hashMapEntry](hashMapEntryLinkField, testSize)
+	entries := make([]hashMapEntry, testSize)
+	for i := range entries {
+		entries[i].data = i
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		for i := range entries {
+			c.Insert(i, &entries[i])
+		}
+		for i := range entries {
+			c.FindFirst(i)
+		}
+		for i := range entries {
+			c.RemoveAllByKey(i)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("unexpected allocations (actual %v != expected 0)", allocs)
+	}
+}
+
+func BenchmarkEmbeddedHashMapStatic_FindFirst(b *testing.B) {
+	const testSize = 1000
+	c := embedded.NewHashMapStatic[int, hashMapEntry](hashMapEntryLinkField, testSize)
+	for i := 0; i < testSize; i++ {
+		c.Insert(i, &hashMapEntry{data: i})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.FindFirst(i % testSize)
+	}
+}
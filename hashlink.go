@@ -8,6 +8,9 @@ import (
 type HashLink[M any] struct {
 	hashNext  *M
 	hashValue HashedKeyValue
+	// owner is the hash containing the item, if any. It makes IsContained
+	// exact, at the cost of a pointer per link.
+	owner *embeddedHash[M]
 }
 
 func getHashLink[T any](obj *T, linkFieldOfs uintptr) *HashLink[T] {
@@ -12,27 +12,65 @@ import (
 type HashList[T any] interface {
 	TableInterface
 	ListInterface[HashedKeyValue, T]
+
+	SpliceAfter(dest *T, src HashList[T], first, last *T)
+	SpliceAll(dest *T, src HashList[T])
+
+	Sort(less func(a, b *T) bool)
+	MergeSorted(other HashList[T], less func(a, b *T) bool)
+
+	Reverse()
+	Rotate(n int)
+	RotateTo(obj *T)
+	SplitAfter(obj *T) HashList[T]
+}
+
+func NewHashListStatic[T any](linkField uintptr, tableSize int, opts ...HashOption) HashList[T] {
+	o := makeHashOptions(opts)
+	o.checkUnkeyed()
+	return newHashListStatic[T](linkField, tableSize, o)
+}
+
+func NewHashListDynamic[T any](linkField uintptr, opts ...HashOption) HashList[T] {
+	o := makeHashOptions(opts)
+	o.checkUnkeyed()
+	return newHashListDynamic[T](linkField, o)
+}
+
+// NewHashListDynamicIncremental creates a hash list with a dynamic table size
+// which resizes incrementally. See NewHashDynamicIncremental.
+func NewHashListDynamicIncremental[T any](linkField uintptr) HashList[T] {
+	return NewHashListDynamic[T](linkField, WithIncrementalResize())
 }
 
-func NewHashListStatic[T any](linkField uintptr, tableSize int) HashList[T] {
+// NewHashListDynamicWithPolicy creates a hash list with a dynamic table size,
+// which is sized according to policy.
+func NewHashListDynamicWithPolicy[T any](linkField uintptr, policy HashTablePolicy) HashList[T] {
+	return NewHashListDynamic[T](linkField, WithHashPolicy(policy))
+}
+
+func newHashListStatic[T any](linkField uintptr, tableSize int, o hashOptions) *embeddedHashList[T] {
 	var hll HashListLink[T]
 	return &embeddedHashList[T]{
-		hash: NewHashStatic[T](linkField+unsafe.Offsetof(hll.hash), tableSize),
-		list: NewList[T](linkField + unsafe.Offsetof(hll.list)),
+		hash:      newHashStatic[T](linkField+unsafe.Offsetof(hll.hash), tableSize, o),
+		list:      NewList[T](linkField + unsafe.Offsetof(hll.list)),
+		linkField: linkField,
 	}
 }
 
-func NewHashListDynamic[T any](linkField uintptr) HashList[T] {
+func newHashListDynamic[T any](linkField uintptr, o hashOptions) *embeddedHashList[T] {
 	var hll HashListLink[T]
 	return &embeddedHashList[T]{
-		hash: NewHashDynamic[T](linkField + unsafe.Offsetof(hll.hash)),
-		list: NewList[T](linkField + unsafe.Offsetof(hll.list)),
+		hash:      newHashDynamic[T](linkField+unsafe.Offsetof(hll.hash), o),
+		list:      NewList[T](linkField + unsafe.Offsetof(hll.list)),
+		linkField: linkField,
 	}
 }
 
 type embeddedHashList[T any] struct {
-	hash Hash[T]
-	list List[T]
+	hash      Hash[T]
+	list      List[T]
+	linkField uintptr
 }
 
 func (c *embeddedHashList[T]) First() *T {
@@ -60,7 +98,9 @@ func (c *embeddedHashList[T]) Count() int {
 }
 
 func (c *embeddedHashList[T]) Remove(obj *T) *T {
-	c.hash.Remove(obj)
+	if c.hash.Remove(obj) == nil {
+		return nil
+	}
 	return c.list.Remove(obj)
 }
 
@@ -145,10 +185,22 @@ func (c *embeddedHashList[T]) GetTableUsed() int {
 	return c.hash.GetTableUsed()
 }
 
+func (c *embeddedHashList[T]) Stats() HashTableStats {
+	return c.hash.Stats()
+}
+
 func (c *embeddedHashList[T]) Reserve(count int) {
 	c.hash.Reserve(count)
 }
 
+func (c *embeddedHashList[T]) Compact() {
+	c.hash.Compact()
+}
+
+func (c *embeddedHashList[T]) Resize(tableSize int) {
+	c.hash.Resize(tableSize)
+}
+
 func (c *embeddedHashList[T]) IsEmpty() bool {
 	return c.hash.IsEmpty()
 }
@@ -156,3 +208,103 @@ func (c *embeddedHashList[T]) IsEmpty() bool {
 func (c *embeddedHashList[T]) IsContained(cur *T) bool {
 	return c.hash.IsContained(cur)
 }
+
+// SpliceAfter moves the items from first through last (inclusive) out of
+// src and into this list after dest, or at the front when dest is nil.
+// Each moved item is rehashed into this list's table, so this is O(k) for
+// k items moved; dest must not be one of the items being moved.
+func (c *embeddedHashList[T]) SpliceAfter(dest *T, src HashList[T], first, last *T) {
+	s := c.spliceSource(src)
+	count := 1
+	for cur := first; cur != last; cur = c.list.Next(cur) {
+		count++
+	}
+	c.list.SpliceAfterCount(dest, s.list, first, last, count)
+	if s != c {
+		c.rehashFrom(s, first, last)
+	}
+}
+
+// SpliceAll moves all items out of src and into this list after dest, or
+// at the front when dest is nil. Each moved item is rehashed, so this is
+// O(n) for n items moved.
+func (c *embeddedHashList[T]) SpliceAll(dest *T, src HashList[T]) {
+	s := c.spliceSource(src)
+	first, last := s.list.First(), s.list.Last()
+	c.list.SpliceAll(dest, s.list)
+	if first != nil {
+		c.rehashFrom(s, first, last)
+	}
+}
+
+func (c *embeddedHashList[T]) spliceSource(src HashList[T]) *embeddedHashList[T] {
+	s, ok := src.(*embeddedHashList[T])
+	if !ok || s.linkField != c.linkField {
+		panic("cannot splice between lists using different links")
+	}
+	return s
+}
+
+// rehashFrom moves the hash entries of the items from first through last,
+// which have already been spliced into this list, out of the table of src.
+func (c *embeddedHashList[T]) rehashFrom(src *embeddedHashList[T], first, last *T) {
+	for cur := first; ; cur = c.list.Next(cur) {
+		hashValue := src.hash.GetKey(cur)
+		src.hash.Remove(cur)
+		c.hash.Insert(hashValue, cur)
+		if cur == last {
+			break
+		}
+	}
+}
+
+// Sort reorders the list with a stable merge sort, relinking the items in
+// place without allocating.
+func (c *embeddedHashList[T]) Sort(less func(a, b *T) bool) {
+	c.list.Sort(less)
+}
+
+// MergeSorted moves all items out of other, merging them into this list.
+// Both lists must already be sorted by less; items of this list are
+// placed ahead of equal items of other.
+func (c *embeddedHashList[T]) MergeSorted(other HashList[T], less func(a, b *T) bool) {
+	s := c.spliceSource(other)
+	if s == c {
+		panic("cannot merge a list into itself")
+	}
+	for cur := s.list.First(); cur != nil; cur = s.list.Next(cur) {
+		hashValue := s.hash.GetKey(cur)
+		s.hash.Remove(cur)
+		c.hash.Insert(hashValue, cur)
+	}
+	c.list.MergeSorted(s.list, less)
+}
+
+func (c *embeddedHashList[T]) Reverse() {
+	c.list.Reverse()
+}
+
+// Rotate moves the first n items to the end of the list, so the item at
+// position n becomes the first; a negative n rotates the other way.
+func (c *embeddedHashList[T]) Rotate(n int) {
+	c.list.Rotate(n)
+}
+
+// RotateTo makes obj the first item, keeping the circular order.
+func (c *embeddedHashList[T]) RotateTo(obj *T) {
+	c.list.RotateTo(obj)
+}
+
+// SplitAfter moves the items after obj into a new hash list with the same
+// kind of table, which is returned. If obj is nil, all items are moved.
+func (c *embeddedHashList[T]) SplitAfter(obj *T) HashList[T] {
+	other := &embeddedHashList[T]{
+		hash:      c.hash.(*embeddedHash[T]).newEmpty(),
+		list:      c.list.SplitAfter(obj),
+		linkField: c.linkField,
+	}
+	if first := other.list.First(); first != nil {
+		other.rehashFrom(c, first, other.list.Last())
+	}
+	return other
+}
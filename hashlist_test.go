@@ -17,7 +17,7 @@ var hashListEntryLinkField = unsafe.Offsetof(hashListEntry{}.link)
 func TestEmbeddedHashListStatic(t *testing.T) {
 	const staticSize = 1000
 	const testSize = int(staticSize * 5.5)
-	const expectedTableUsed = 996
+	const expectedTableUsed = 1000
 	const removeTarget = (testSize / 2) - 1
 	c := embedded.NewHashListStatic[hashListEntry](hashListEntryLinkField, staticSize)
 	testEmbeddedHashList(t, c, testSize, expectedTableUsed, staticSize, removeTarget)
@@ -25,13 +25,140 @@ func TestEmbeddedHashListStatic(t *testing.T) {
 
 func TestEmbeddedHashListDynamic(t *testing.T) {
 	const testSize = 5500
-	const expectedTableUsed = 4116
+	const expectedTableUsed = 3976
 	const expectedTableSize = 8192 // next power of 2 over 5500
 	const removeTarget = (testSize / 2) - 1
 	c := embedded.NewHashListDynamic[hashListEntry](hashListEntryLinkField)
 	testEmbeddedHashList(t, c, testSize, expectedTableUsed, expectedTableSize, removeTarget)
 }
 
+func TestEmbeddedHashListContainment(t *testing.T) {
+	a := embedded.NewHashListDynamic[hashListEntry](hashListEntryLinkField)
+	b := embedded.NewHashListDynamic[hashListEntry](hashListEntryLinkField)
+	for i := 0; i < 10; i++ {
+		a.InsertLast(embedded.HashKey(i), &hashListEntry{data: i})
+	}
+
+	entry := a.FindFirst(embedded.HashKey(5))
+	if b.IsContained(entry) || b.Remove(entry) != nil {
+		t.Fatal("embedded hash list removed an item of another list")
+	}
+	if a.Count() != 10 || !a.IsContained(entry) {
+		t.Fatal("removal from another list changed the contents")
+	}
+	for i, cur := 0, a.First(); cur != nil; i, cur = i+1, a.Next(cur) {
+		if cur.data != i {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.data, i)
+		}
+	}
+}
+
+func TestEmbeddedHashListSplice(t *testing.T) {
+	const testSize = 100
+	a := embedded.NewHashListDynamic[hashListEntry](hashListEntryLinkField)
+	b := embedded.NewHashListStatic[hashListEntry](hashListEntryLinkField, 10)
+	for i := 0; i < testSize; i++ {
+		a.InsertLast(embedded.HashKey(i), &hashListEntry{data: i})
+	}
+
+	b.SpliceAfter(nil, a, a.Position(10), a.Position(19))
+	b.SpliceAfter(b.Last(), a, a.Position(50), a.Last())
+	if actualCount := a.Count(); actualCount != 50 {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, 50)
+	}
+	if actualCount := b.Count(); actualCount != 50 {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, 50)
+	}
+
+	for i := 0; i < testSize; i++ {
+		inB := (i >= 10 && i < 20) || i >= 60
+		owner, other := a, b
+		if inB {
+			owner, other = b, a
+		}
+		entry := owner.FindFirst(embedded.HashKey(i))
+		if entry == nil || entry.data != i {
+			t.Fatal("expected entry not found in hash after splice")
+		}
+		if other.FindFirst(embedded.HashKey(i)) != nil {
+			t.Fatal("spliced entry still found in source hash")
+		}
+	}
+
+	expected := 10
+	for cur := b.First(); cur != nil; cur = b.Next(cur) {
+		if cur.data != expected {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.data, expected)
+		}
+		if expected++; expected == 20 {
+			expected = 60
+		}
+	}
+
+	a.SpliceAll(a.First(), b)
+	if !b.IsEmpty() || b.First() != nil {
+		t.Fatal("embedded hash list is not empty after splicing all items out")
+	}
+	if actualCount := a.Count(); actualCount != testSize {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize)
+	}
+	if entry := a.FindFirst(embedded.HashKey(75)); entry == nil || entry.data != 75 {
+		t.Fatal("expected entry not found in hash after splice")
+	}
+	if second := a.Next(a.First()); second == nil || second.data != 10 {
+		t.Fatal("spliced items not found after destination")
+	}
+}
+
+func TestEmbeddedHashListSplitAfter(t *testing.T) {
+	const testSize = 100
+	const staticSize = 10
+	for _, static := range []bool{true, false} {
+		c := embedded.NewHashListDynamic[hashListEntry](hashListEntryLinkField)
+		if static {
+			c = embedded.NewHashListStatic[hashListEntry](hashListEntryLinkField, staticSize)
+		}
+		for i := 0; i < testSize; i++ {
+			c.InsertLast(embedded.HashKey(i), &hashListEntry{data: i})
+		}
+		c.Reverse()
+		c.Rotate(testSize / 2)
+
+		// the list is now 49..0, 99..50
+		other := c.SplitAfter(c.FindFirst(embedded.HashKey(0)))
+		if actualCount := c.Count(); actualCount != testSize/2 {
+			t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize/2)
+		}
+		if actualCount := other.Count(); actualCount != testSize/2 {
+			t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize/2)
+		}
+		if actualTableSize := other.GetTableSize(); static && actualTableSize != staticSize {
+			t.Fatalf("unexpected table size (actual %d != expected %d)", actualTableSize, staticSize)
+		}
+
+		for i := 0; i < testSize; i++ {
+			owner, notOwner := c, other
+			if i >= testSize/2 {
+				owner, notOwner = other, c
+			}
+			entry := owner.FindFirst(embedded.HashKey(i))
+			if entry == nil || entry.data != i {
+				t.Fatal("expected entry not found in hash after split")
+			}
+			if notOwner.FindFirst(embedded.HashKey(i)) != nil {
+				t.Fatal("split entry found in the wrong hash")
+			}
+		}
+
+		other.Reverse()
+		for i, cur := testSize/2, other.First(); cur != nil; i, cur = i+1, other.Next(cur) {
+			if cur.data != i {
+				t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.data, i)
+			}
+		}
+	}
+}
+
 func BenchmarkEmbeddedHashListStatic_InsertFirst(b *testing.B) {
 	hash := embedded.NewHashListStatic[hashListEntry](hashListEntryLinkField, b.N)
 	b.ReportAllocs()
@@ -6,6 +6,10 @@ import (
 
 // This is a combination double-linked list and hash table container - it allows
 // for fast lookup via a hash value and linear iteration over its contents.
+// The *Hashed methods are their namesakes taking a key which has already been
+// hashed, by NewHashedKey or HashKey (see HashedKey). A seeded hash list map
+// only skips hashing the keys returned by its own HashKey since it last
+// reseeded, and hashes any other key again.
 // This cointainer does not take ownership of its contents, so the application
 // must remove items manually.
 
@@ -15,33 +19,149 @@ type HashListMap[TKey HashMapKeyType, T any] interface {
 
 	RemoveAllByKey(key TKey)
 	RemoveAllByUniqueKey(key TKey)
+
+	FindFirstHashed(key HashedKey[TKey]) *T
+	InsertFirstHashed(key HashedKey[TKey], cur *T) *T
+	InsertLastHashed(key HashedKey[TKey], cur *T) *T
+	InsertAfterHashed(key HashedKey[TKey], prev, cur *T) *T
+	InsertBeforeHashed(key HashedKey[TKey], after, cur *T) *T
+	MoveHashed(obj *T, newKey HashedKey[TKey])
+	RemoveAllByKeyHashed(key HashedKey[TKey])
+	HashKey(key TKey) HashedKey[TKey]
+
+	InsertUnique(key TKey, obj *T) (existing *T, inserted bool)
+	Upsert(key TKey, obj *T) (replaced *T)
+	UpsertInPlace(key TKey, obj *T) (replaced *T)
+
+	SpliceAfter(dest *T, src HashListMap[TKey, T], first, last *T)
+	SpliceAll(dest *T, src HashListMap[TKey, T])
+
+	Sort(less func(a, b *T) bool)
+	MergeSorted(other HashListMap[TKey, T], less func(a, b *T) bool)
+
+	Reverse()
+	Rotate(n int)
+	RotateTo(obj *T)
+	SplitAfter(obj *T) HashListMap[TKey, T]
 }
 
-func NewHashListMapStatic[TKey HashMapKeyType, T any](linkField uintptr, tableSize int) HashListMap[TKey, T] {
+func NewHashListMapStatic[TKey HashMapKeyType, T any](linkField uintptr, tableSize int, opts ...HashOption) HashListMap[TKey, T] {
 	var hlml HashListMapLink[TKey, T]
-	return &embeddedHashListMap[TKey, T]{
-		hashList:  NewHashListStatic[T](linkField+unsafe.Offsetof(hlml.hashList), tableSize),
-		linkField: linkField,
-	}
+	o := makeHashOptions(opts)
+	return newHashListMap[TKey](linkField, newHashListStatic[T](linkField+unsafe.Offsetof(hlml.hashList), tableSize, o), o)
 }
 
-func NewHashListMapDynamic[TKey HashMapKeyType, T any](linkField uintptr) HashListMap[TKey, T] {
+func NewHashListMapDynamic[TKey HashMapKeyType, T any](linkField uintptr, opts ...HashOption) HashListMap[TKey, T] {
 	var hlml HashListMapLink[TKey, T]
-	return &embeddedHashListMap[TKey, T]{
-		hashList:  NewHashListDynamic[T](linkField + unsafe.Offsetof(hlml.hashList)),
+	o := makeHashOptions(opts)
+	return newHashListMap[TKey](linkField, newHashListDynamic[T](linkField+unsafe.Offsetof(hlml.hashList), o), o)
+}
+
+// NewHashListMapStaticWithHasher creates a hash list map with a static table
+// size, which hashes its keys with hasher. Its HashedKey values and items are
+// its own; to share them, or to splice or merge items between hash list maps,
+// create them with a single WithHasher option value.
+func NewHashListMapStaticWithHasher[TKey HashMapKeyType, T any](linkField uintptr, tableSize int, hasher Hasher[TKey]) HashListMap[TKey, T] {
+	return NewHashListMapStatic[TKey, T](linkField, tableSize, WithHasher(hasher))
+}
+
+// NewHashListMapDynamicWithHasher creates a hash list map with a dynamic table
+// size, which hashes its keys with hasher. See
+// NewHashListMapStaticWithHasher.
+func NewHashListMapDynamicWithHasher[TKey HashMapKeyType, T any](linkField uintptr, hasher Hasher[TKey]) HashListMap[TKey, T] {
+	return NewHashListMapDynamic[TKey, T](linkField, WithHasher(hasher))
+}
+
+// NewHashListMapDynamicIncremental creates a hash list map with a dynamic
+// table size which resizes incrementally. See NewHashDynamicIncremental.
+func NewHashListMapDynamicIncremental[TKey HashMapKeyType, T any](linkField uintptr) HashListMap[TKey, T] {
+	return NewHashListMapDynamic[TKey, T](linkField, WithIncrementalResize())
+}
+
+// NewHashListMapDynamicWithPolicy creates a hash list map with a dynamic table
+// size, which is sized according to policy.
+func NewHashListMapDynamicWithPolicy[TKey HashMapKeyType, T any](linkField uintptr, policy HashTablePolicy) HashListMap[TKey, T] {
+	return NewHashListMapDynamic[TKey, T](linkField, WithHashPolicy(policy))
+}
+
+// NewHashListMapStaticUnique creates a hash list map with a static table
+// size, which holds at most one item per key. See WithUniqueKeys.
+func NewHashListMapStaticUnique[TKey HashMapKeyType, T any](linkField uintptr, tableSize int) HashListMap[TKey, T] {
+	return NewHashListMapStatic[TKey, T](linkField, tableSize, WithUniqueKeys())
+}
+
+// NewHashListMapDynamicUnique creates a hash list map with a dynamic table
+// size, which holds at most one item per key. See WithUniqueKeys.
+func NewHashListMapDynamicUnique[TKey HashMapKeyType, T any](linkField uintptr) HashListMap[TKey, T] {
+	return NewHashListMapDynamic[TKey, T](linkField, WithUniqueKeys())
+}
+
+// NewHashListMapStaticSeeded creates a hash list map with a static table
+// size, which hashes its keys with hash/maphash under a random seed of its
+// own. See WithSeeding.
+func NewHashListMapStaticSeeded[TKey HashMapKeyType, T any](linkField uintptr, tableSize int) HashListMap[TKey, T] {
+	return NewHashListMapStatic[TKey, T](linkField, tableSize, WithSeeding())
+}
+
+// NewHashListMapDynamicSeeded creates a hash list map with a dynamic table
+// size, which hashes its keys with hash/maphash under a random seed of its
+// own. See WithSeeding.
+func NewHashListMapDynamicSeeded[TKey HashMapKeyType, T any](linkField uintptr) HashListMap[TKey, T] {
+	return NewHashListMapDynamic[TKey, T](linkField, WithSeeding())
+}
+
+func newHashListMap[TKey HashMapKeyType, T any](linkField uintptr, hashList *embeddedHashList[T], o hashOptions) *embeddedHashListMap[TKey, T] {
+	c := &embeddedHashListMap[TKey, T]{
+		hashList:  hashList,
 		linkField: linkField,
+		hasher:    hasherOf[TKey](o),
+		domain:    o.domain,
+		unique:    o.unique,
+	}
+	if o.seeded {
+		c.enableSeeding()
 	}
+	return c
 }
 
 type embeddedHashListMap[TKey HashMapKeyType, T any] struct {
 	hashList  HashList[T]
 	linkField uintptr
+	hasher    Hasher[TKey]
+	domain    *hashDomain
+	seeded    *seededHasher[TKey]
+	unique    bool
 }
 
 func (c *embeddedHashListMap[TKey, T]) getLink(obj *T) *HashListMapLink[TKey, T] {
 	return getHashListMapLink[TKey](obj, c.linkField)
 }
 
+func (c *embeddedHashListMap[TKey, T]) getHash() *embeddedHash[T] {
+	return c.hashList.(*embeddedHashList[T]).hash.(*embeddedHash[T])
+}
+
+func (c *embeddedHashListMap[TKey, T]) enableSeeding() {
+	c.seeded = newSeededHasher[TKey]()
+	c.hasher = c.seeded.hash
+	c.domain = new(hashDomain)
+	hash := c.getHash()
+	hash.maxChainLength = seededHashMaxChainLength
+	hash.onLongChain = c.reseed
+}
+
+// reseed picks a new seed and rehashes every item under it.
+func (c *embeddedHashListMap[TKey, T]) reseed() {
+	c.seeded.reseed()
+	// keys hashed under the old seed are hashed again from now on
+	c.domain = new(hashDomain)
+	c.getHash().rehashAll(func(obj *T) HashedKeyValue {
+		objLink := c.getLink(obj)
+		objLink.key = c.HashKey(objLink.key.value).linkKey
+		return objLink.key.hash
+	})
+}
+
 func (c *embeddedHashListMap[TKey, T]) First() *T {
 	return c.hashList.First()
 }
@@ -83,12 +203,19 @@ func (c *embeddedHashListMap[TKey, T]) RemoveAll() {
 }
 
 func (c *embeddedHashListMap[TKey, T]) RemoveAllByKey(key TKey) {
-	hashValue := newHashKey(key).hash
-	cur := c.hashList.FindFirst(hashValue)
+	c.removeAllByKeyHashed(c.HashKey(key))
+}
+
+func (c *embeddedHashListMap[TKey, T]) RemoveAllByKeyHashed(key HashedKey[TKey]) {
+	c.removeAllByKeyHashed(c.ownKey(key))
+}
+
+func (c *embeddedHashListMap[TKey, T]) removeAllByKeyHashed(hashedKey HashedKey[TKey]) {
+	cur := c.hashList.FindFirst(hashedKey.hash)
 	for cur != nil {
 		next := c.hashList.FindNext(cur)
 		curLink := c.getLink(cur)
-		if curLink.key.value == key {
+		if curLink.key.value == hashedKey.value {
 			c.hashList.Remove(cur)
 		}
 		cur = next
@@ -96,7 +223,7 @@ func (c *embeddedHashListMap[TKey, T]) RemoveAllByKey(key TKey) {
 }
 
 func (c *embeddedHashListMap[TKey, T]) RemoveAllByUniqueKey(key TKey) {
-	hashValue := newHashKey(key).hash
+	hashValue := c.HashKey(key).hash
 	cur := c.hashList.FindFirst(hashValue)
 	for cur != nil {
 		next := c.hashList.FindNext(cur)
@@ -109,59 +236,177 @@ func (c *embeddedHashListMap[TKey, T]) RemoveAllByUniqueKey(key TKey) {
 	}
 }
 
-func (c *embeddedHashListMap[TKey, T]) InsertFirst(key TKey, cur *T) *T {
-	hashedKey := newHashKey(key)
-	obj := c.hashList.InsertFirst(hashedKey.hash, cur)
-	if obj == nil {
-		return nil
+// ownKey returns hashedKey as hashed by this hash list map. A seeded hash list map hashes
+// again any key it did not hash under its current seed, as no other hasher
+// shares the seed.
+func (c *embeddedHashListMap[TKey, T]) ownKey(hashedKey HashedKey[TKey]) HashedKey[TKey] {
+	if hashedKey.domain == c.domain {
+		return hashedKey
 	}
+	if c.seeded != nil {
+		return c.HashKey(hashedKey.value)
+	}
+	panic("cannot use a key hashed by another hasher")
+}
 
-	objLink := c.getLink(obj)
-	objLink.key = hashedKey
-	return obj
+// HashKey hashes key with the hasher of this container, for its *Hashed
+// methods and those of containers sharing the hasher. The key of a seeded
+// container saves hashing only until the container reseeds.
+func (c *embeddedHashListMap[TKey, T]) HashKey(key TKey) HashedKey[TKey] {
+	return newHashedKey(key, c.hasher, c.domain)
 }
 
-func (c *embeddedHashListMap[TKey, T]) InsertLast(key TKey, cur *T) *T {
-	hashedKey := newHashKey(key)
-	obj := c.hashList.InsertLast(hashedKey.hash, cur)
-	if obj == nil {
-		return nil
+// find returns the first item with the key of hashedKey.
+func (c *embeddedHashListMap[TKey, T]) find(hashedKey HashedKey[TKey]) *T {
+	for cur := c.hashList.FindFirst(hashedKey.hash); cur != nil; cur = c.hashList.FindNext(cur) {
+		if c.getLink(cur).key.value == hashedKey.value {
+			return cur
+		}
 	}
+	return nil
+}
 
-	objLink := c.getLink(obj)
-	objLink.key = hashedKey
-	return obj
+// prepareInsert stores key in cur ahead of inserting it, as the insert may
+// reseed and rehash the items. In unique mode, an item with an equal key which
+// is already contained is returned instead.
+func (c *embeddedHashListMap[TKey, T]) prepareInsert(hashedKey HashedKey[TKey], cur *T) *T {
+	if c.unique {
+		if existing := c.find(hashedKey); existing != nil {
+			return existing
+		}
+	}
+	c.getLink(cur).key = hashedKey.linkKey
+	return nil
 }
 
-func (c *embeddedHashListMap[TKey, T]) InsertAfter(key TKey, prev, cur *T) *T {
-	hashedKey := newHashKey(key)
-	obj := c.hashList.InsertAfter(hashedKey.hash, prev, cur)
-	if obj == nil {
-		return nil
+func (c *embeddedHashListMap[TKey, T]) InsertFirst(key TKey, cur *T) *T {
+	return c.insertFirstHashed(c.HashKey(key), cur)
+}
+
+func (c *embeddedHashListMap[TKey, T]) InsertFirstHashed(key HashedKey[TKey], cur *T) *T {
+	return c.insertFirstHashed(c.ownKey(key), cur)
+}
+
+func (c *embeddedHashListMap[TKey, T]) insertFirstHashed(hashedKey HashedKey[TKey], cur *T) *T {
+	if existing := c.prepareInsert(hashedKey, cur); existing != nil {
+		return existing
 	}
+	return c.hashList.InsertFirst(c.getLink(cur).key.hash, cur)
+}
 
-	objLink := c.getLink(obj)
-	objLink.key = hashedKey
-	return obj
+func (c *embeddedHashListMap[TKey, T]) InsertLast(key TKey, cur *T) *T {
+	return c.insertLastHashed(c.HashKey(key), cur)
+}
+
+func (c *embeddedHashListMap[TKey, T]) InsertLastHashed(key HashedKey[TKey], cur *T) *T {
+	return c.insertLastHashed(c.ownKey(key), cur)
+}
+
+func (c *embeddedHashListMap[TKey, T]) insertLastHashed(hashedKey HashedKey[TKey], cur *T) *T {
+	if existing := c.prepareInsert(hashedKey, cur); existing != nil {
+		return existing
+	}
+	return c.hashList.InsertLast(c.getLink(cur).key.hash, cur)
+}
+
+func (c *embeddedHashListMap[TKey, T]) InsertAfter(key TKey, prev, cur *T) *T {
+	return c.insertAfterHashed(c.HashKey(key), prev, cur)
+}
+
+func (c *embeddedHashListMap[TKey, T]) InsertAfterHashed(key HashedKey[TKey], prev, cur *T) *T {
+	return c.insertAfterHashed(c.ownKey(key), prev, cur)
+}
+
+func (c *embeddedHashListMap[TKey, T]) insertAfterHashed(hashedKey HashedKey[TKey], prev, cur *T) *T {
+	if existing := c.prepareInsert(hashedKey, cur); existing != nil {
+		return existing
+	}
+	return c.hashList.InsertAfter(c.getLink(cur).key.hash, prev, cur)
 }
 
 func (c *embeddedHashListMap[TKey, T]) InsertBefore(key TKey, after, cur *T) *T {
-	hashedKey := newHashKey(key)
-	obj := c.hashList.InsertBefore(hashedKey.hash, after, cur)
-	if obj == nil {
-		return nil
+	return c.insertBeforeHashed(c.HashKey(key), after, cur)
+}
+
+func (c *embeddedHashListMap[TKey, T]) InsertBeforeHashed(key HashedKey[TKey], after, cur *T) *T {
+	return c.insertBeforeHashed(c.ownKey(key), after, cur)
+}
+
+func (c *embeddedHashListMap[TKey, T]) insertBeforeHashed(hashedKey HashedKey[TKey], after, cur *T) *T {
+	if existing := c.prepareInsert(hashedKey, cur); existing != nil {
+		return existing
 	}
+	return c.hashList.InsertBefore(c.getLink(cur).key.hash, after, cur)
+}
 
-	objLink := c.getLink(obj)
-	objLink.key = hashedKey
-	return obj
+// InsertUnique inserts cur at the end of the list unless an item with an
+// equal key is already contained, in which case that item is returned
+// instead.
+func (c *embeddedHashListMap[TKey, T]) InsertUnique(key TKey, cur *T) (*T, bool) {
+	hashedKey := c.HashKey(key)
+	if existing := c.find(hashedKey); existing != nil {
+		return existing, false
+	}
+	c.getLink(cur).key = hashedKey.linkKey
+	c.hashList.InsertLast(hashedKey.hash, cur)
+	return nil, true
+}
+
+// Upsert inserts cur at the end of the list, removing the item with an
+// equal key if one is contained. The removed item is returned. If cur is
+// already the item stored under key, nothing is replaced and nil is
+// returned.
+func (c *embeddedHashListMap[TKey, T]) Upsert(key TKey, cur *T) *T {
+	hashedKey := c.HashKey(key)
+	replaced := c.find(hashedKey)
+	if replaced == cur {
+		// already stored under key, so nothing is replaced
+		return nil
+	}
+	if replaced != nil {
+		c.hashList.Remove(replaced)
+	}
+	c.getLink(cur).key = hashedKey.linkKey
+	c.hashList.InsertLast(hashedKey.hash, cur)
+	return replaced
+}
+
+// UpsertInPlace is Upsert, except that cur takes the place in the list of
+// the item it replaces.
+func (c *embeddedHashListMap[TKey, T]) UpsertInPlace(key TKey, cur *T) *T {
+	hashedKey := c.HashKey(key)
+	replaced := c.find(hashedKey)
+	if replaced == cur {
+		// already stored under key, so nothing is replaced
+		return nil
+	}
+	c.getLink(cur).key = hashedKey.linkKey
+	if replaced == nil {
+		c.hashList.InsertLast(hashedKey.hash, cur)
+		return nil
+	}
+	c.hashList.InsertAfter(hashedKey.hash, replaced, cur)
+	c.hashList.Remove(replaced)
+	return replaced
 }
 
 func (c *embeddedHashListMap[TKey, T]) Move(obj *T, newKey TKey) {
-	hashedKey := newHashKey(newKey)
-	c.hashList.Move(obj, hashedKey.hash)
+	c.moveHashed(obj, c.HashKey(newKey))
+}
+
+func (c *embeddedHashListMap[TKey, T]) MoveHashed(obj *T, newKey HashedKey[TKey]) {
+	c.moveHashed(obj, c.ownKey(newKey))
+}
+
+func (c *embeddedHashListMap[TKey, T]) moveHashed(obj *T, hashedKey HashedKey[TKey]) {
+	if c.unique {
+		if existing := c.find(hashedKey); existing != nil && existing != obj {
+			panic("cannot move an item to a key which is already in use")
+		}
+	}
 	objLink := c.getLink(obj)
-	objLink.key = hashedKey
+	objLink.key = hashedKey.linkKey
+	c.hashList.Move(obj, objLink.key.hash)
 }
 
 func (c *embeddedHashListMap[TKey, T]) MoveFirst(cur *T) {
@@ -181,10 +426,14 @@ func (c *embeddedHashListMap[TKey, T]) MoveBefore(dest, cur *T) {
 }
 
 func (c *embeddedHashListMap[TKey, T]) FindFirst(key TKey) *T {
-	hashedKey := newHashKey(key)
+	hashedKey := c.HashKey(key)
 	return c.hashList.FindFirst(hashedKey.hash)
 }
 
+func (c *embeddedHashListMap[TKey, T]) FindFirstHashed(key HashedKey[TKey]) *T {
+	return c.hashList.FindFirst(c.ownKey(key).hash)
+}
+
 func (c *embeddedHashListMap[TKey, T]) FindNext(prevResult *T) *T {
 	return c.hashList.FindNext(prevResult)
 }
@@ -201,10 +450,22 @@ func (c *embeddedHashListMap[TKey, T]) GetTableUsed() int {
 	return c.hashList.GetTableUsed()
 }
 
+func (c *embeddedHashListMap[TKey, T]) Stats() HashTableStats {
+	return c.hashList.Stats()
+}
+
 func (c *embeddedHashListMap[TKey, T]) Reserve(count int) {
 	c.hashList.Reserve(count)
 }
 
+func (c *embeddedHashListMap[TKey, T]) Compact() {
+	c.hashList.Compact()
+}
+
+func (c *embeddedHashListMap[TKey, T]) Resize(tableSize int) {
+	c.hashList.Resize(tableSize)
+}
+
 func (c *embeddedHashListMap[TKey, T]) IsEmpty() bool {
 	return c.hashList.IsEmpty()
 }
@@ -212,3 +473,79 @@ func (c *embeddedHashListMap[TKey, T]) IsEmpty() bool {
 func (c *embeddedHashListMap[TKey, T]) IsContained(cur *T) bool {
 	return c.hashList.IsContained(cur)
 }
+
+// SpliceAfter moves the items from first through last (inclusive) out of
+// src and into this list after dest, or at the front when dest is nil.
+// Each moved item is rehashed into this list's table, so this is O(k) for
+// k items moved; dest must not be one of the items being moved.
+func (c *embeddedHashListMap[TKey, T]) SpliceAfter(dest *T, src HashListMap[TKey, T], first, last *T) {
+	c.hashList.SpliceAfter(dest, c.spliceSource(src).hashList, first, last)
+}
+
+// SpliceAll moves all items out of src and into this list after dest, or
+// at the front when dest is nil. Each moved item is rehashed, so this is
+// O(n) for n items moved.
+func (c *embeddedHashListMap[TKey, T]) SpliceAll(dest *T, src HashListMap[TKey, T]) {
+	c.hashList.SpliceAll(dest, c.spliceSource(src).hashList)
+}
+
+func (c *embeddedHashListMap[TKey, T]) spliceSource(src HashListMap[TKey, T]) *embeddedHashListMap[TKey, T] {
+	s, ok := src.(*embeddedHashListMap[TKey, T])
+	if !ok || s.linkField != c.linkField {
+		panic("cannot splice between lists using different links")
+	}
+	if s.seeded != c.seeded {
+		panic("cannot splice between lists using different seeds")
+	}
+	if s.domain != c.domain {
+		panic("cannot splice between lists using different hashers")
+	}
+	return s
+}
+
+// Sort reorders the list with a stable merge sort, relinking the items in
+// place without allocating.
+func (c *embeddedHashListMap[TKey, T]) Sort(less func(a, b *T) bool) {
+	c.hashList.Sort(less)
+}
+
+// MergeSorted moves all items out of other, merging them into this list.
+// Both lists must already be sorted by less; items of this list are
+// placed ahead of equal items of other.
+func (c *embeddedHashListMap[TKey, T]) MergeSorted(other HashListMap[TKey, T], less func(a, b *T) bool) {
+	c.hashList.MergeSorted(c.spliceSource(other).hashList, less)
+}
+
+func (c *embeddedHashListMap[TKey, T]) Reverse() {
+	c.hashList.Reverse()
+}
+
+// Rotate moves the first n items to the end of the list, so the item at
+// position n becomes the first; a negative n rotates the other way.
+func (c *embeddedHashListMap[TKey, T]) Rotate(n int) {
+	c.hashList.Rotate(n)
+}
+
+// RotateTo makes obj the first item, keeping the circular order.
+func (c *embeddedHashListMap[TKey, T]) RotateTo(obj *T) {
+	c.hashList.RotateTo(obj)
+}
+
+// SplitAfter moves the items after obj into a new hash list map with the
+// same kind of table, which is returned. If obj is nil, all items are
+// moved.
+func (c *embeddedHashListMap[TKey, T]) SplitAfter(obj *T) HashListMap[TKey, T] {
+	other := &embeddedHashListMap[TKey, T]{
+		hashList:  c.hashList.SplitAfter(obj),
+		linkField: c.linkField,
+		hasher:    c.hasher,
+		domain:    c.domain,
+		unique:    c.unique,
+	}
+	if c.seeded != nil {
+		// the moved items were hashed under the seed of this hash list map
+		other.enableSeeding()
+		other.reseed()
+	}
+	return other
+}
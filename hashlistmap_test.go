@@ -17,7 +17,7 @@ var hashListMapEntryLinkField = unsafe.Offsetof(hashListMapEntry{}.link)
 func TestEmbeddedHashListMapStatic(t *testing.T) {
 	const staticSize = 1000
 	const testSize = int(staticSize * 5.5)
-	const expectedTableUsed = 996
+	const expectedTableUsed = 1000
 	const removeTarget = (testSize / 2) - 1
 	c := embedded.NewHashListMapStatic[int, hashListMapEntry](hashListMapEntryLinkField, staticSize)
 	testEmbeddedHashListMap(t, c, testSize, expectedTableUsed, staticSize, removeTarget)
@@ -25,13 +25,288 @@ func TestEmbeddedHashListMapStatic(t *testing.T) {
 
 func TestEmbeddedHashListMapDynamic(t *testing.T) {
 	const testSize = 5500
-	const expectedTableUsed = 4116
+	const expectedTableUsed = 3976
 	const expectedTableSize = 8192 // next power of 2 over 5500
 	const removeTarget = (testSize / 2) - 1
 	c := embedded.NewHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField)
 	testEmbeddedHashListMap(t, c, testSize, expectedTableUsed, expectedTableSize, removeTarget)
 }
 
+func TestEmbeddedHashListMapDynamicWithPolicy(t *testing.T) {
+	const testSize = 5500
+	const expectedTableUsed = 1019
+	const expectedTableSize = 1024
+	const removeTarget = (testSize / 2) - 1
+	c := embedded.NewHashListMapDynamicWithPolicy[int, hashListMapEntry](hashListMapEntryLinkField, embedded.HashTablePolicy{
+		MaxSize: 1024,
+	})
+	testEmbeddedHashListMap(t, c, testSize, expectedTableUsed, expectedTableSize, removeTarget)
+}
+
+func TestEmbeddedHashListMapSplice(t *testing.T) {
+	const testSize = 100
+	a := embedded.NewHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField)
+	b := embedded.NewHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField)
+	for i := 0; i < testSize; i++ {
+		a.InsertLast(i, &hashListMapEntry{data: i})
+	}
+
+	b.SpliceAfter(nil, a, a.FindFirst(20), a.FindFirst(29))
+	if actualCount := b.Count(); actualCount != 10 {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, 10)
+	}
+	for i := 20; i < 30; i++ {
+		entry := b.FindFirst(i)
+		if entry == nil || entry.data != i || b.GetKey(entry) != i {
+			t.Fatal("expected entry not found after splice")
+		}
+		if a.FindFirst(i) != nil {
+			t.Fatal("spliced entry still found in source")
+		}
+	}
+
+	b.RemoveAllByKey(25)
+	a.SpliceAll(a.FindFirst(19), b)
+	if !b.IsEmpty() {
+		t.Fatal("embedded hash list map is not empty after splicing all items out")
+	}
+	if actualCount := a.Count(); actualCount != testSize-1 {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize-1)
+	}
+	for i, cur := 0, a.First(); cur != nil; i, cur = i+1, a.Next(cur) {
+		if i == 25 {
+			i++
+		}
+		if cur.data != i || a.FindFirst(i) != cur {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.data, i)
+		}
+	}
+}
+
+func TestEmbeddedHashListMapUnique(t *testing.T) {
+	c := embedded.NewHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField)
+	entries := make([]hashListMapEntry, 6)
+	for i := range entries[:4] {
+		entries[i].data = i
+		if existing, inserted := c.InsertUnique(i, &entries[i]); existing != nil || !inserted {
+			t.Fatal("unique item was not inserted")
+		}
+	}
+	if existing, inserted := c.InsertUnique(2, &entries[4]); existing != &entries[2] || inserted {
+		t.Fatal("item with a duplicate key was inserted")
+	}
+
+	entries[4].data = 4
+	if replaced := c.UpsertInPlace(1, &entries[4]); replaced != &entries[1] {
+		t.Fatal("upsert did not replace the item with an equal key")
+	}
+	entries[5].data = 5
+	if replaced := c.Upsert(2, &entries[5]); replaced != &entries[2] {
+		t.Fatal("upsert did not replace the item with an equal key")
+	}
+	testEmbeddedHashListMapOrder(t, c, []int{0, 4, 3, 5})
+	if c.FindFirst(1) != &entries[4] || c.FindFirst(2) != &entries[5] {
+		t.Fatal("upserted item not found by its key")
+	}
+	if c.Upsert(2, &entries[5]) != nil || c.UpsertInPlace(1, &entries[4]) != nil {
+		t.Fatal("upsert of the stored item reported it as replaced")
+	}
+	testEmbeddedHashListMapOrder(t, c, []int{0, 4, 3, 5})
+
+	u := embedded.NewHashListMapStaticUnique[int, hashListMapEntry](hashListMapEntryLinkField, 10)
+	u.InsertLast(1, &entries[1])
+	if existing := u.InsertFirst(1, &entries[2]); existing != &entries[1] || u.IsContained(&entries[2]) {
+		t.Fatal("unique hash list map inserted an item with a duplicate key")
+	}
+	u.InsertLast(2, &entries[2])
+	expectPanic(t, func() { u.Move(&entries[2], 1) })
+}
+
+func TestEmbeddedHashListMapHashed(t *testing.T) {
+	keys := make([]embedded.HashedKey[int], 4)
+	for i := range keys {
+		keys[i] = embedded.NewHashedKey(i)
+	}
+
+	plain := embedded.NewHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField)
+	seeded := embedded.NewHashListMapDynamicSeeded[int, hashListMapEntry](hashListMapEntryLinkField)
+	for _, c := range []embedded.HashListMap[int, hashListMapEntry]{plain, seeded} {
+		entries := make([]hashListMapEntry, 5)
+		for i := range entries {
+			entries[i].data = i
+		}
+		c.InsertLastHashed(keys[1], &entries[1])
+		c.InsertFirstHashed(keys[0], &entries[0])
+		c.InsertAfterHashed(keys[2], &entries[1], &entries[2])
+		c.InsertBeforeHashed(keys[3], &entries[2], &entries[3])
+		for i, key := range keys {
+			if found := c.FindFirstHashed(key); found != &entries[i] || c.FindFirst(i) != found {
+				t.Fatal("item inserted by hashed key not found")
+			}
+		}
+		c.InsertLast(3, &entries[4])
+		testEmbeddedHashListMapOrder(t, c, []int{0, 1, 3, 2, 4})
+
+		c.MoveHashed(&entries[4], keys[0])
+		if found := c.FindFirstHashed(keys[0]); c.GetKey(&entries[4]) != 0 || found == nil || c.FindNext(found) == nil {
+			t.Fatal("moved item not found by its new key")
+		}
+		c.RemoveAllByKeyHashed(keys[0])
+		testEmbeddedHashListMapOrder(t, c, []int{1, 3, 2})
+	}
+}
+
+func testEmbeddedHashListMapOrder(t *testing.T, c embedded.HashListMap[int, hashListMapEntry], expected []int) {
+	t.Helper()
+	if actualCount := c.Count(); actualCount != len(expected) {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, len(expected))
+	}
+	for i, cur := 0, c.First(); cur != nil; i, cur = i+1, c.Next(cur) {
+		if cur.data != expected[i] {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.data, expected[i])
+		}
+	}
+}
+
+func TestEmbeddedHashListMapSeeded(t *testing.T) {
+	const testSize = 1000
+	keys := collidingHashKeys(2048, testSize)
+
+	c := embedded.NewHashListMapStaticSeeded[int, hashListMapEntry](hashListMapEntryLinkField, 2048)
+	for _, key := range keys {
+		c.InsertLast(key, &hashListMapEntry{data: key})
+	}
+	if actualTableUsed := c.GetTableUsed(); actualTableUsed < testSize/2 {
+		t.Fatalf("colliding keys not spread by the seeded hash list map (table used %d)", actualTableUsed)
+	}
+
+	other := c.SplitAfter(c.FindFirst(keys[testSize/2-1]))
+	if stats := other.Stats(); stats.ReseedCount != 1 || stats.ResizeCount != 0 {
+		t.Fatalf("unexpected reseed and resize counts after split (actual %d and %d != expected 1 and 0)", stats.ReseedCount, stats.ResizeCount)
+	}
+	for i, key := range keys {
+		owner, notOwner := c, other
+		if i >= testSize/2 {
+			owner, notOwner = other, c
+		}
+		if entry := owner.FindFirst(key); entry == nil || entry.data != key {
+			t.Fatal("expected entry not found after split")
+		}
+		if notOwner.FindFirst(key) != nil {
+			t.Fatal("entry found in the wrong hash list map after split")
+		}
+	}
+
+	expectPanic(t, func() { c.SpliceAll(nil, other) })
+	unseeded := embedded.NewHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField)
+	expectPanic(t, func() { unseeded.SpliceAll(nil, c) })
+}
+
+func TestEmbeddedHashListMapSort(t *testing.T) {
+	const testSize = 1000
+	a := embedded.NewHashListMapStatic[int, hashListMapEntry](hashListMapEntryLinkField, 100)
+	b := embedded.NewHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField)
+	for i := testSize - 1; i >= 0; i-- {
+		if i%2 == 0 {
+			a.InsertLast(i, &hashListMapEntry{data: i})
+		} else {
+			b.InsertFirst(i, &hashListMapEntry{data: i})
+		}
+	}
+
+	less := func(x, y *hashListMapEntry) bool {
+		return x.data < y.data
+	}
+	a.Sort(less)
+	for i, cur := 0, a.First(); cur != nil; i, cur = i+2, a.Next(cur) {
+		if cur.data != i || a.FindFirst(i) != cur {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.data, i)
+		}
+	}
+
+	a.MergeSorted(b, less)
+	if !b.IsEmpty() {
+		t.Fatal("embedded hash list map is not empty after merging its items out")
+	}
+	if actualCount := a.Count(); actualCount != testSize {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize)
+	}
+	for i, cur := 0, a.First(); cur != nil; i, cur = i+1, a.Next(cur) {
+		if cur.data != i || a.FindFirst(i) != cur {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.data, i)
+		}
+		if b.FindFirst(i) != nil {
+			t.Fatal("merged entry still found in source")
+		}
+	}
+}
+
+func TestEmbeddedBoundedHashListMap(t *testing.T) {
+	const capacity = 100
+	c := embedded.NewBoundedHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField, capacity, embedded.OverflowEvictFirst, nil)
+	for i := 0; i < capacity*3; i++ {
+		dropped := c.InsertLast(i, &hashListMapEntry{data: i})
+		if i < capacity && dropped != nil {
+			t.Fatal("unexpected item dropped below capacity")
+		}
+		if i >= capacity && (dropped == nil || dropped.data != i-capacity) {
+			t.Fatal("oldest item not evicted")
+		}
+	}
+	if actualCount := c.Count(); actualCount != capacity {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, capacity)
+	}
+	for i := 0; i < capacity*3; i++ {
+		entry := c.FindFirst(i)
+		if i < capacity*2 && entry != nil {
+			t.Fatal("evicted entry still found in hash")
+		}
+		if i >= capacity*2 && (entry == nil || entry.data != i) {
+			t.Fatal("expected entry not found")
+		}
+	}
+
+	static := embedded.NewBoundedHashListMapStatic[int, hashListMapEntry](hashListMapEntryLinkField, 10, 2, embedded.OverflowReject, nil)
+	static.InsertLast(1, &hashListMapEntry{data: 1})
+	static.InsertLast(2, &hashListMapEntry{data: 2})
+	incoming := &hashListMapEntry{data: 3}
+	if dropped := static.InsertFirst(3, incoming); dropped != incoming {
+		t.Fatal("incoming item not rejected")
+	}
+	if static.FindFirst(3) != nil || static.IsContained(incoming) || !static.IsFull() {
+		t.Fatal("rejected item was inserted")
+	}
+}
+
+func TestEmbeddedBoundedHashListMapOverflowCallback(t *testing.T) {
+	const capacity = 2
+	other := embedded.NewHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField)
+	foreign := other.InsertLast(-1, &hashListMapEntry{data: -1})
+	var victim *hashListMapEntry
+	c := embedded.NewBoundedHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField, capacity, embedded.OverflowCallback, func(incoming *hashListMapEntry) *hashListMapEntry {
+		return victim
+	})
+	for i := 0; i < capacity; i++ {
+		c.InsertLast(i, &hashListMapEntry{data: i})
+	}
+
+	incoming := &hashListMapEntry{data: capacity}
+	if dropped := c.InsertLast(capacity, incoming); dropped != incoming {
+		t.Fatal("incoming item not rejected when the callback returns nil")
+	}
+	if c.Count() != capacity || c.FindFirst(capacity) != nil {
+		t.Fatal("rejected item was inserted")
+	}
+
+	victim = foreign
+	expectPanic(t, func() {
+		c.InsertLast(capacity, incoming)
+	})
+	if c.Count() != capacity || other.Count() != 1 || !other.IsContained(foreign) {
+		t.Fatal("foreign victim was removed")
+	}
+}
+
 func BenchmarkEmbeddedHashListMapStatic_InsertLast(b *testing.B) {
 	hash := embedded.NewHashListMapStatic[int, hashListMapEntry](hashListMapEntryLinkField, b.N)
 	b.ReportAllocs()
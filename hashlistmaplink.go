@@ -7,7 +7,7 @@ import (
 // HashListMapLink is a link to the map container
 type HashListMapLink[TKey HashMapKeyType, T any] struct {
 	hashList HashListLink[T]
-	key      hashKey[TKey]
+	key      linkKey[TKey]
 }
 
 func getHashListMapLink[TKey HashMapKeyType, T any](obj *T, linkFieldOfs uintptr) *HashListMapLink[TKey, T] {
@@ -5,6 +5,11 @@ import (
 )
 
 // This is a hash map container - it allows for fast lookups of its contents.
+// As with embedded.Hash, items must not be removed during a walk with WalkFirst
+// and WalkNext. The *Hashed methods are their namesakes taking a key which has
+// already been hashed, by NewHashedKey or HashKey (see HashedKey). A seeded
+// hash map only skips hashing the keys returned by its own HashKey since it
+// last reseeded, and hashes any other key again.
 // This cointainer does not take ownership of its contents, so the application
 // must remove items manually.
 
@@ -14,6 +19,8 @@ type HashMap[TKey HashMapKeyType, T any] interface {
 	Remove(obj *T) *T
 
 	Insert(key TKey, obj *T) *T
+	InsertUnique(key TKey, obj *T) (existing *T, inserted bool)
+	Upsert(key TKey, obj *T) (replaced *T)
 
 	Move(obj *T, newKey TKey)
 
@@ -26,44 +33,206 @@ type HashMap[TKey HashMapKeyType, T any] interface {
 	RemoveAllByKey(key TKey)
 	RemoveAllByUniqueKey(key TKey)
 
+	FindFirstHashed(key HashedKey[TKey]) *T
+	InsertHashed(key HashedKey[TKey], obj *T) *T
+	MoveHashed(obj *T, newKey HashedKey[TKey])
+	RemoveAllByKeyHashed(key HashedKey[TKey])
+	HashKey(key TKey) HashedKey[TKey]
+
 	WalkFirst() *T
 	WalkNext(prevResult *T) *T
 }
 
-func NewHashMapStatic[TKey HashMapKeyType, T any](linkField uintptr, tableSize int) HashMap[TKey, T] {
+func NewHashMapStatic[TKey HashMapKeyType, T any](linkField uintptr, tableSize int, opts ...HashOption) HashMap[TKey, T] {
 	var hml HashMapLink[TKey, T]
-	return &embeddedHashMap[TKey, T]{
-		hash:      NewHashStatic[T](linkField+unsafe.Offsetof(hml.link), tableSize),
-		linkField: linkField,
-	}
+	o := makeHashOptions(opts)
+	return newHashMap[TKey](linkField, newHashStatic[T](linkField+unsafe.Offsetof(hml.link), tableSize, o), o)
 }
 
-func NewHashMapDynamic[TKey HashMapKeyType, T any](linkField uintptr) HashMap[TKey, T] {
+func NewHashMapDynamic[TKey HashMapKeyType, T any](linkField uintptr, opts ...HashOption) HashMap[TKey, T] {
 	var hml HashMapLink[TKey, T]
-	return &embeddedHashMap[TKey, T]{
-		hash:      NewHashDynamic[T](linkField + unsafe.Offsetof(hml.link)),
+	o := makeHashOptions(opts)
+	return newHashMap[TKey](linkField, newHashDynamic[T](linkField+unsafe.Offsetof(hml.link), o), o)
+}
+
+// NewHashMapStaticWithHasher creates a hash map with a static table size,
+// which hashes its keys with hasher. Its HashedKey values are its own; to share
+// them between hash maps, create them with a single WithHasher option value.
+func NewHashMapStaticWithHasher[TKey HashMapKeyType, T any](linkField uintptr, tableSize int, hasher Hasher[TKey]) HashMap[TKey, T] {
+	return NewHashMapStatic[TKey, T](linkField, tableSize, WithHasher(hasher))
+}
+
+// NewHashMapDynamicWithHasher creates a hash map with a dynamic table size,
+// which hashes its keys with hasher. See NewHashMapStaticWithHasher.
+func NewHashMapDynamicWithHasher[TKey HashMapKeyType, T any](linkField uintptr, hasher Hasher[TKey]) HashMap[TKey, T] {
+	return NewHashMapDynamic[TKey, T](linkField, WithHasher(hasher))
+}
+
+// NewHashMapDynamicIncremental creates a hash map with a dynamic table size
+// which resizes incrementally. See NewHashDynamicIncremental.
+func NewHashMapDynamicIncremental[TKey HashMapKeyType, T any](linkField uintptr) HashMap[TKey, T] {
+	return NewHashMapDynamic[TKey, T](linkField, WithIncrementalResize())
+}
+
+// NewHashMapDynamicWithPolicy creates a hash map with a dynamic table size,
+// which is sized according to policy.
+func NewHashMapDynamicWithPolicy[TKey HashMapKeyType, T any](linkField uintptr, policy HashTablePolicy) HashMap[TKey, T] {
+	return NewHashMapDynamic[TKey, T](linkField, WithHashPolicy(policy))
+}
+
+// NewHashMapStaticUnique creates a hash map with a static table size, which
+// holds at most one item per key. See WithUniqueKeys.
+func NewHashMapStaticUnique[TKey HashMapKeyType, T any](linkField uintptr, tableSize int) HashMap[TKey, T] {
+	return NewHashMapStatic[TKey, T](linkField, tableSize, WithUniqueKeys())
+}
+
+// NewHashMapDynamicUnique creates a hash map with a dynamic table size, which
+// holds at most one item per key. See WithUniqueKeys.
+func NewHashMapDynamicUnique[TKey HashMapKeyType, T any](linkField uintptr) HashMap[TKey, T] {
+	return NewHashMapDynamic[TKey, T](linkField, WithUniqueKeys())
+}
+
+// NewHashMapStaticSeeded creates a hash map with a static table size, which
+// hashes its keys with hash/maphash under a random seed of its own. See
+// WithSeeding.
+func NewHashMapStaticSeeded[TKey HashMapKeyType, T any](linkField uintptr, tableSize int) HashMap[TKey, T] {
+	return NewHashMapStatic[TKey, T](linkField, tableSize, WithSeeding())
+}
+
+// NewHashMapDynamicSeeded creates a hash map with a dynamic table size, which
+// hashes its keys with hash/maphash under a random seed of its own. See
+// WithSeeding.
+func NewHashMapDynamicSeeded[TKey HashMapKeyType, T any](linkField uintptr) HashMap[TKey, T] {
+	return NewHashMapDynamic[TKey, T](linkField, WithSeeding())
+}
+
+func newHashMap[TKey HashMapKeyType, T any](linkField uintptr, hash *embeddedHash[T], o hashOptions) *embeddedHashMap[TKey, T] {
+	c := &embeddedHashMap[TKey, T]{
+		hash:      hash,
 		linkField: linkField,
+		hasher:    hasherOf[TKey](o),
+		domain:    o.domain,
+		unique:    o.unique,
 	}
+	if o.seeded {
+		c.enableSeeding()
+	}
+	return c
 }
 
 type embeddedHashMap[TKey HashMapKeyType, T any] struct {
 	hash      Hash[T]
 	linkField uintptr
+	hasher    Hasher[TKey]
+	domain    *hashDomain
+	seeded    *seededHasher[TKey]
+	unique    bool
 }
 
 func (c *embeddedHashMap[TKey, T]) getLink(obj *T) *HashMapLink[TKey, T] {
 	return getHashMapLink[TKey](obj, c.linkField)
 }
 
+func (c *embeddedHashMap[TKey, T]) enableSeeding() {
+	c.seeded = newSeededHasher[TKey]()
+	c.hasher = c.seeded.hash
+	c.domain = new(hashDomain)
+	hash := c.hash.(*embeddedHash[T])
+	hash.maxChainLength = seededHashMaxChainLength
+	hash.onLongChain = c.reseed
+}
+
+// reseed picks a new seed and rehashes every item under it.
+func (c *embeddedHashMap[TKey, T]) reseed() {
+	c.seeded.reseed()
+	// keys hashed under the old seed are hashed again from now on
+	c.domain = new(hashDomain)
+	c.hash.(*embeddedHash[T]).rehashAll(func(obj *T) HashedKeyValue {
+		objLink := c.getLink(obj)
+		objLink.key = c.HashKey(objLink.key.value).linkKey
+		return objLink.key.hash
+	})
+}
+
+// ownKey returns hashedKey as hashed by this hash map. A seeded hash map hashes
+// again any key it did not hash under its current seed, as no other hasher
+// shares the seed.
+func (c *embeddedHashMap[TKey, T]) ownKey(hashedKey HashedKey[TKey]) HashedKey[TKey] {
+	if hashedKey.domain == c.domain {
+		return hashedKey
+	}
+	if c.seeded != nil {
+		return c.HashKey(hashedKey.value)
+	}
+	panic("cannot use a key hashed by another hasher")
+}
+
+// HashKey hashes key with the hasher of this container, for its *Hashed
+// methods and those of containers sharing the hasher. The key of a seeded
+// container saves hashing only until the container reseeds.
+func (c *embeddedHashMap[TKey, T]) HashKey(key TKey) HashedKey[TKey] {
+	return newHashedKey(key, c.hasher, c.domain)
+}
+
+// find returns the first item with the key of hashedKey.
+func (c *embeddedHashMap[TKey, T]) find(hashedKey HashedKey[TKey]) *T {
+	for cur := c.hash.FindFirst(hashedKey.hash); cur != nil; cur = c.hash.FindNext(cur) {
+		if c.getLink(cur).key.value == hashedKey.value {
+			return cur
+		}
+	}
+	return nil
+}
+
+func (c *embeddedHashMap[TKey, T]) insert(hashedKey HashedKey[TKey], obj *T) *T {
+	// the key is stored first, as the insert may reseed and rehash the items
+	c.getLink(obj).key = hashedKey.linkKey
+	return c.hash.Insert(hashedKey.hash, obj)
+}
+
 func (c *embeddedHashMap[TKey, T]) Insert(key TKey, obj *T) *T {
-	hashedKey := newHashKey(key)
-	o := c.hash.Insert(hashedKey.hash, obj)
-	if o == nil {
+	return c.insertHashed(c.HashKey(key), obj)
+}
+
+func (c *embeddedHashMap[TKey, T]) InsertHashed(key HashedKey[TKey], obj *T) *T {
+	return c.insertHashed(c.ownKey(key), obj)
+}
+
+func (c *embeddedHashMap[TKey, T]) insertHashed(hashedKey HashedKey[TKey], obj *T) *T {
+	if c.unique {
+		if existing := c.find(hashedKey); existing != nil {
+			return existing
+		}
+	}
+	return c.insert(hashedKey, obj)
+}
+
+// InsertUnique inserts obj unless an item with an equal key is already
+// contained, in which case that item is returned instead.
+func (c *embeddedHashMap[TKey, T]) InsertUnique(key TKey, obj *T) (*T, bool) {
+	hashedKey := c.HashKey(key)
+	if existing := c.find(hashedKey); existing != nil {
+		return existing, false
+	}
+	c.insert(hashedKey, obj)
+	return nil, true
+}
+
+// Upsert inserts obj, removing the item with an equal key if one is
+// contained. The removed item is returned. If obj is already the item
+// stored under key, nothing is replaced and nil is returned.
+func (c *embeddedHashMap[TKey, T]) Upsert(key TKey, obj *T) *T {
+	hashedKey := c.HashKey(key)
+	replaced := c.find(hashedKey)
+	if replaced == obj {
+		// already stored under key, so nothing is replaced
 		return nil
 	}
-	oLink := c.getLink(o)
-	oLink.key = hashedKey
-	return o
+	if replaced != nil {
+		c.hash.Remove(replaced)
+	}
+	c.insert(hashedKey, obj)
+	return replaced
 }
 
 func (c *embeddedHashMap[TKey, T]) Remove(obj *T) *T {
@@ -71,13 +240,28 @@ func (c *embeddedHashMap[TKey, T]) Remove(obj *T) *T {
 }
 
 func (c *embeddedHashMap[TKey, T]) Move(obj *T, newKey TKey) {
-	hashedKey := newHashKey(newKey)
-	c.hash.Move(obj, hashedKey.hash)
 	if obj == nil {
 		return
 	}
+	c.moveHashed(obj, c.HashKey(newKey))
+}
+
+func (c *embeddedHashMap[TKey, T]) MoveHashed(obj *T, newKey HashedKey[TKey]) {
+	if obj == nil {
+		return
+	}
+	c.moveHashed(obj, c.ownKey(newKey))
+}
+
+func (c *embeddedHashMap[TKey, T]) moveHashed(obj *T, hashedKey HashedKey[TKey]) {
+	if c.unique {
+		if existing := c.find(hashedKey); existing != nil && existing != obj {
+			panic("cannot move an item to a key which is already in use")
+		}
+	}
 	objLink := c.getLink(obj)
-	objLink.key = hashedKey
+	objLink.key = hashedKey.linkKey
+	c.hash.Move(obj, objLink.key.hash)
 }
 
 func (c *embeddedHashMap[TKey, T]) RemoveAll() {
@@ -85,12 +269,19 @@ func (c *embeddedHashMap[TKey, T]) RemoveAll() {
 }
 
 func (c *embeddedHashMap[TKey, T]) RemoveAllByKey(key TKey) {
-	hashedKey := newHashKey(key)
+	c.removeAllByKeyHashed(c.HashKey(key))
+}
+
+func (c *embeddedHashMap[TKey, T]) RemoveAllByKeyHashed(key HashedKey[TKey]) {
+	c.removeAllByKeyHashed(c.ownKey(key))
+}
+
+func (c *embeddedHashMap[TKey, T]) removeAllByKeyHashed(hashedKey HashedKey[TKey]) {
 	cur := c.hash.FindFirst(hashedKey.hash)
 	for cur != nil {
 		next := c.hash.FindNext(cur)
 		curLink := c.getLink(cur)
-		if curLink.key.value == key {
+		if curLink.key.value == hashedKey.value {
 			c.hash.Remove(cur)
 		}
 		cur = next
@@ -98,7 +289,7 @@ func (c *embeddedHashMap[TKey, T]) RemoveAllByKey(key TKey) {
 }
 
 func (c *embeddedHashMap[TKey, T]) RemoveAllByUniqueKey(key TKey) {
-	hashedKey := newHashKey(key)
+	hashedKey := c.HashKey(key)
 	cur := c.hash.FindFirst(hashedKey.hash)
 	for cur != nil {
 		next := c.hash.FindNext(cur)
@@ -115,6 +306,14 @@ func (c *embeddedHashMap[TKey, T]) Reserve(count int) {
 	c.hash.Reserve(count)
 }
 
+func (c *embeddedHashMap[TKey, T]) Compact() {
+	c.hash.Compact()
+}
+
+func (c *embeddedHashMap[TKey, T]) Resize(tableSize int) {
+	c.hash.Resize(tableSize)
+}
+
 func (c *embeddedHashMap[TKey, T]) GetKey(obj *T) TKey {
 	objLink := c.getLink(obj)
 	return objLink.key.value
@@ -132,22 +331,20 @@ func (c *embeddedHashMap[TKey, T]) GetTableUsed() int {
 	return c.hash.GetTableUsed()
 }
 
+func (c *embeddedHashMap[TKey, T]) Stats() HashTableStats {
+	return c.hash.Stats()
+}
+
 func (c *embeddedHashMap[TKey, T]) IsEmpty() bool {
 	return c.hash.IsEmpty()
 }
 
 func (c *embeddedHashMap[TKey, T]) FindFirst(key TKey) *T {
-	hashedKey := newHashKey(key)
-	cur := c.hash.FindFirst(hashedKey.hash)
-	for cur != nil {
-		next := c.hash.FindNext(cur)
-		curLink := c.getLink(cur)
-		if curLink.key.value == key {
-			return cur
-		}
-		cur = next
-	}
-	return nil
+	return c.find(c.HashKey(key))
+}
+
+func (c *embeddedHashMap[TKey, T]) FindFirstHashed(key HashedKey[TKey]) *T {
+	return c.find(c.ownKey(key))
 }
 
 func (c *embeddedHashMap[TKey, T]) FindNext(prevResult *T) *T {
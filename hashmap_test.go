@@ -17,7 +17,7 @@ var hashMapEntryLinkField = unsafe.Offsetof(hashMapEntry{}.link)
 func TestEmbeddedHashMapStatic(t *testing.T) {
 	const staticSize = 1000
 	const testSize = int(staticSize * 5.5)
-	const expectedTableUsed = 996
+	const expectedTableUsed = 1000
 	const removeTarget = (testSize / 2) - 1
 	c := embedded.NewHashMapStatic[int, hashMapEntry](hashMapEntryLinkField, staticSize)
 	testEmbeddedHashMap(t, c, testSize, expectedTableUsed, staticSize, removeTarget)
@@ -25,13 +25,224 @@ func TestEmbeddedHashMapStatic(t *testing.T) {
 
 func TestEmbeddedHashMapDynamic(t *testing.T) {
 	const testSize = 5500
-	const expectedTableUsed = 4116
+	const expectedTableUsed = 3976
 	const expectedTableSize = 8192 // next power of 2 over 5500
 	const removeTarget = (testSize / 2) - 1
 	c := embedded.NewHashMapDynamic[int, hashMapEntry](hashMapEntryLinkField)
 	testEmbeddedHashMap(t, c, testSize, expectedTableUsed, expectedTableSize, removeTarget)
 }
 
+func TestEmbeddedHashMapDynamicWithPolicy(t *testing.T) {
+	const testSize = 5500
+	const expectedTableUsed = 1359
+	const expectedTableSize = 1391 // grown by a quarter from 8 until holding 4 items per bucket
+	const removeTarget = (testSize / 2) - 1
+	c := embedded.NewHashMapDynamicWithPolicy[int, hashMapEntry](hashMapEntryLinkField, embedded.HashTablePolicy{
+		LoadFactor:   4,
+		GrowthFactor: 1.25,
+		ExactSize:    true,
+	})
+	testEmbeddedHashMap(t, c, testSize, expectedTableUsed, expectedTableSize, removeTarget)
+}
+
+func TestEmbeddedHashMapUnique(t *testing.T) {
+	c := embedded.NewHashMapDynamic[int, hashMapEntry](hashMapEntryLinkField)
+	first := &hashMapEntry{data: 1}
+	if existing, inserted := c.InsertUnique(1, first); existing != nil || !inserted {
+		t.Fatal("unique item was not inserted")
+	}
+	if existing, inserted := c.InsertUnique(1, &hashMapEntry{data: 2}); existing != first || inserted {
+		t.Fatal("item with a duplicate key was inserted")
+	}
+
+	second := &hashMapEntry{data: 3}
+	if replaced := c.Upsert(1, second); replaced != first {
+		t.Fatal("upsert did not replace the item with an equal key")
+	}
+	if replaced := c.Upsert(2, first); replaced != nil {
+		t.Fatal("upsert replaced an item without an equal key")
+	}
+	if !c.IsContained(first) || c.FindFirst(1) != second || c.FindNext(second) != nil || c.Count() != 2 {
+		t.Fatal("unexpected contents after upsert")
+	}
+	if replaced := c.Upsert(1, second); replaced != nil || !c.IsContained(second) || c.Count() != 2 {
+		t.Fatal("upsert of the stored item reported it as replaced")
+	}
+
+	u := embedded.NewHashMapDynamicUnique[int, hashMapEntry](hashMapEntryLinkField)
+	u.Insert(1, first)
+	if existing := u.Insert(1, second); existing != first || u.IsContained(second) {
+		t.Fatal("unique hash map inserted an item with a duplicate key")
+	}
+	u.Insert(2, second)
+	expectPanic(t, func() { u.Move(second, 1) })
+	u.Move(second, 3)
+	if u.FindFirst(3) != second || u.Count() != 2 {
+		t.Fatal("moved item not found by its new key")
+	}
+}
+
+func TestEmbeddedHashMapOptions(t *testing.T) {
+	const testSize = 5500
+	c := embedded.NewHashMapDynamic[int, hashMapEntry](hashMapEntryLinkField,
+		embedded.WithSeeding(),
+		embedded.WithUniqueKeys(),
+		embedded.WithIncrementalResize(),
+		embedded.WithHashPolicy(embedded.HashTablePolicy{MaxSize: 1024}),
+	)
+	for i := 0; i < testSize; i++ {
+		c.Insert(i, &hashMapEntry{data: i})
+		if existing := c.Insert(i, &hashMapEntry{data: -1}); existing == nil || existing.data != i {
+			t.Fatal("unique hash map inserted an item with a duplicate key")
+		}
+	}
+	if actualTableSize := c.GetTableSize(); actualTableSize != 1024 {
+		t.Fatalf("unexpected table size (actual %d != expected %d)", actualTableSize, 1024)
+	}
+	for i := 0; i < testSize; i++ {
+		if entry := c.FindFirst(i); entry == nil || entry.data != i || c.FindNext(entry) != nil {
+			t.Fatal("expected entry not found")
+		}
+	}
+
+	h := embedded.NewHashMapDynamic[int, hashMapEntry](hashMapEntryLinkField, embedded.WithHasher(embedded.HashKeyXX[int]), embedded.WithIncrementalResize())
+	for i := 0; i < testSize; i++ {
+		h.Insert(i, &hashMapEntry{data: i})
+	}
+	for i := 0; i < testSize; i++ {
+		if entry := h.FindFirst(i); entry == nil || entry.data != i {
+			t.Fatal("expected entry not found")
+		}
+	}
+
+	expectPanic(t, func() {
+		embedded.NewHashMapStatic[int, hashMapEntry](hashMapEntryLinkField, 10, embedded.WithIncrementalResize())
+	})
+	expectPanic(t, func() {
+		embedded.NewHashMapDynamic[int, hashMapEntry](hashMapEntryLinkField, embedded.WithSeeding(), embedded.WithHasher(embedded.HashKeyXX[int]))
+	})
+	expectPanic(t, func() {
+		embedded.NewHashMapDynamic[int, hashMapEntry](hashMapEntryLinkField, embedded.WithHasher(embedded.HashKeyXX[string]))
+	})
+	expectPanic(t, func() {
+		embedded.NewHashDynamic[hashEntry](hashEntryLinkField, embedded.WithUniqueKeys())
+	})
+}
+
+func TestEmbeddedHashMapHashed(t *testing.T) {
+	key := embedded.NewHashedKey(7)
+	if key.Key() != 7 || key.Hash() != embedded.HashKey(7) {
+		t.Fatal("hashed key does not hold the key and its hash")
+	}
+
+	plain := embedded.NewHashMapDynamic[int, hashMapEntry](hashMapEntryLinkField)
+	seeded := embedded.NewHashMapDynamicSeeded[int, hashMapEntry](hashMapEntryLinkField)
+	for _, c := range []embedded.HashMap[int, hashMapEntry]{plain, seeded} {
+		first := c.InsertHashed(key, &hashMapEntry{data: 1})
+		second := c.Insert(7, &hashMapEntry{data: 2})
+		found := c.FindFirstHashed(key)
+		if found != c.FindFirst(7) || (found != first && found != second) || c.FindNext(c.FindNext(found)) != nil {
+			t.Fatal("item inserted by hashed key not found")
+		}
+
+		c.MoveHashed(second, embedded.NewHashedKey(8))
+		if c.GetKey(second) != 8 || c.FindFirst(8) != second || c.FindFirstHashed(key) != first || c.FindNext(first) != nil {
+			t.Fatal("moved item not found by its new key")
+		}
+		c.RemoveAllByKeyHashed(key)
+		if c.FindFirst(7) != nil || c.Count() != 1 {
+			t.Fatal("items not removed by hashed key")
+		}
+	}
+
+	xx := embedded.WithHasher(embedded.HashKeyXX[int])
+	a := embedded.NewHashMapDynamic[int, hashMapEntry](hashMapEntryLinkField, xx)
+	b := embedded.NewHashListMapDynamic[int, hashListMapEntry](hashListMapEntryLinkField, xx)
+	other := embedded.NewHashMapDynamic[int, hashMapEntry](hashMapEntryLinkField, embedded.WithHasher(embedded.HashKeyXX[int]))
+	xxKey := a.HashKey(7)
+	entry := a.InsertHashed(xxKey, &hashMapEntry{data: 7})
+	listEntry := b.InsertLastHashed(xxKey, &hashListMapEntry{data: 7})
+	if a.FindFirst(7) != entry || b.FindFirst(7) != listEntry || a.FindFirstHashed(b.HashKey(7)) != entry {
+		t.Fatal("item inserted by a key of a shared hasher not found")
+	}
+	expectPanic(t, func() { a.FindFirstHashed(key) })
+	expectPanic(t, func() { other.InsertHashed(xxKey, &hashMapEntry{data: 7}) })
+	expectPanic(t, func() { plain.FindFirstHashed(xxKey) })
+
+	seededKey := seeded.HashKey(9)
+	if entry := seeded.InsertHashed(seededKey, &hashMapEntry{data: 9}); seeded.FindFirstHashed(seededKey) != entry || seeded.FindFirst(9) != entry {
+		t.Fatal("item inserted by a key of the seeded hash map not found")
+	}
+	if plain.FindFirstHashed(embedded.NewHashedKey(9)) != nil || seeded.FindFirstHashed(embedded.NewHashedKey(9)) == nil {
+		t.Fatal("seeded hash map did not hash a foreign key again")
+	}
+}
+
+func TestEmbeddedHashMapLinkSize(t *testing.T) {
+	var key int
+	var hash embedded.HashedKeyValue
+	linkSize := unsafe.Sizeof(hashMapEntry{}.link)
+	expectedSize := unsafe.Sizeof(embedded.HashLink[hashMapEntry]{}) + unsafe.Sizeof(key) + unsafe.Sizeof(hash)
+	if linkSize > expectedSize {
+		t.Fatalf("hash map link is larger than expected (actual %d > expected %d)", linkSize, expectedSize)
+	}
+}
+
+func TestEmbeddedHashMapSeeded(t *testing.T) {
+	const testSize = 1000
+	keys := collidingHashKeys(2048, testSize)
+
+	unseeded := embedded.NewHashMapDynamic[int, hashMapEntry](hashMapEntryLinkField)
+	seeded := embedded.NewHashMapDynamicSeeded[int, hashMapEntry](hashMapEntryLinkField)
+	for _, key := range keys {
+		unseeded.Insert(key, &hashMapEntry{data: key})
+		seeded.Insert(key, &hashMapEntry{data: key})
+		seeded.Insert(keys[0], &hashMapEntry{data: keys[0]})
+	}
+
+	if actualTableUsed := unseeded.GetTableUsed(); actualTableUsed != 1 {
+		t.Fatalf("unexpected table used size (actual %d != expected %d)", actualTableUsed, 1)
+	}
+	if actualTableUsed := seeded.GetTableUsed(); actualTableUsed < testSize/2 {
+		t.Fatalf("colliding keys not spread by the seeded hash map (table used %d)", actualTableUsed)
+	}
+
+	for _, key := range keys[1:] {
+		entry := seeded.FindFirst(key)
+		if entry == nil || entry.data != key || seeded.FindNext(entry) != nil {
+			t.Fatal("expected entry not found")
+		}
+		seeded.Move(entry, -key)
+		if seeded.FindFirst(key) != nil || seeded.FindFirst(-key) != entry {
+			t.Fatal("moved entry not found by its new key")
+		}
+	}
+	var duplicates int
+	for cur := seeded.FindFirst(keys[0]); cur != nil; cur = seeded.FindNext(cur) {
+		duplicates++
+	}
+	if duplicates != testSize+1 {
+		t.Fatalf("unexpected duplicate count (actual %d != expected %d)", duplicates, testSize+1)
+	}
+	testEmbeddedHashStats(t, seeded.Stats())
+	seeded.RemoveAllByKey(keys[0])
+	if actualCount := seeded.Count(); actualCount != testSize-1 {
+		t.Fatalf("unexpected hash count (actual %d != expected %d)", actualCount, testSize-1)
+	}
+}
+
+// collidingHashKeys returns count keys which HashKey places in the same bucket
+// of any table with a power of 2 size up to tableSize, as an attacker could.
+func collidingHashKeys(tableSize, count int) []int {
+	var keys []int
+	for key := 0; len(keys) < count; key++ {
+		if embedded.HashKey(key)%embedded.HashedKeyValue(tableSize) == 0 {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 func BenchmarkEmbeddedHashMapStatic_Insert(b *testing.B) {
 	hash := embedded.NewHashMapStatic[int, hashMapEntry](hashMapEntryLinkField, b.N)
 	b.ReportAllocs()
@@ -7,7 +7,7 @@ import (
 // HashMapLink is a link to the list container
 type HashMapLink[TKey HashMapKeyType, M any] struct {
 	link HashLink[M]
-	key  hashKey[TKey]
+	key  linkKey[TKey]
 }
 
 func getHashMapLink[TKey HashMapKeyType, T any](obj *T, linkFieldOfs uintptr) *HashMapLink[TKey, T] {
@@ -0,0 +1,103 @@
+package embedded
+
+// HashOption configures a hashed container as it is created by one of its
+// Static or Dynamic constructors. Any number of options may be combined, but
+// passing an option which does not apply to the container panics.
+type HashOption func(o *hashOptions)
+
+type hashOptions struct {
+	policy      HashTablePolicy
+	incremental bool
+	hasher      any
+	domain      *hashDomain
+	seeded      bool
+	unique      bool
+}
+
+func makeHashOptions(opts []HashOption) hashOptions {
+	var o hashOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithHashPolicy sizes a dynamic table according to policy.
+func WithHashPolicy(policy HashTablePolicy) HashOption {
+	return func(o *hashOptions) {
+		o.policy = policy
+	}
+}
+
+// WithIncrementalResize makes a dynamic table resize incrementally: the old
+// table is kept alongside the new one after a resize, and each following
+// insert or removal migrates a few of its buckets.
+func WithIncrementalResize() HashOption {
+	return func(o *hashOptions) {
+		o.incremental = true
+	}
+}
+
+// WithHasher makes a hash map or hash list map hash its keys with hasher
+// instead of HashKey. Containers created with the same option value share the
+// hasher, so the HashedKey values of one can be passed to the others, and
+// items can be spliced or merged between such hash list maps.
+func WithHasher[TKey HashMapKeyType](hasher Hasher[TKey]) HashOption {
+	domain := new(hashDomain)
+	return func(o *hashOptions) {
+		o.hasher = hasher
+		o.domain = domain
+	}
+}
+
+// WithSeeding makes a hash map or hash list map hash its keys with
+// hash/maphash under a random seed of its own. This keeps the bucket of a key
+// unpredictable, so keys supplied by an untrusted source cannot be chosen to
+// pile up in a single chain. Should a chain grow long anyway, the container
+// picks a new seed and rehashes its contents. Items may not be spliced or
+// merged between seeded hash list maps, as each uses a different seed.
+func WithSeeding() HashOption {
+	return func(o *hashOptions) {
+		o.seeded = true
+	}
+}
+
+// WithUniqueKeys makes a hash map or hash list map hold at most one item per
+// key: the Insert methods return the contained item rather than adding one
+// with an equal key, and Move panics when the new key is already in use. Items
+// spliced or merged into a hash list map are not checked against the keys
+// already contained.
+func WithUniqueKeys() HashOption {
+	return func(o *hashOptions) {
+		o.unique = true
+	}
+}
+
+// checkStatic panics if o configures a dynamic table.
+func (o hashOptions) checkStatic() {
+	if o.policy != (HashTablePolicy{}) || o.incremental {
+		panic("hash table policy and incremental resizing require a dynamic table")
+	}
+}
+
+// checkUnkeyed panics if o configures the keys of a hash map.
+func (o hashOptions) checkUnkeyed() {
+	if o.hasher != nil || o.seeded || o.unique {
+		panic("hashers, seeding and unique keys require a hash map or hash list map")
+	}
+}
+
+// hasherOf returns the hasher configured by o, or HashKey by default.
+func hasherOf[TKey HashMapKeyType](o hashOptions) Hasher[TKey] {
+	if o.hasher == nil {
+		return HashKey[TKey]
+	}
+	if o.seeded {
+		panic("a seeded hash map cannot use another hasher")
+	}
+	hasher, ok := o.hasher.(Hasher[TKey])
+	if !ok || hasher == nil {
+		panic("hasher does not match the key type of the hash map")
+	}
+	return hasher
+}
@@ -0,0 +1,57 @@
+package embedded
+
+import (
+	"github.com/heucuva/go-embedded-container/internal/array"
+)
+
+// HashTablePolicy controls how a dynamic hash table is sized as items are
+// inserted and removed. A zero field takes its default, so the zero policy
+// sizes tables just as NewHashDynamic does.
+type HashTablePolicy struct {
+	// LoadFactor is the number of items per bucket which the table grows to
+	// stay under. A lower load factor shortens chains at the cost of memory.
+	// The default is 0.8.
+	LoadFactor float64
+	// GrowthFactor is the least factor by which the table grows once it has
+	// to. A larger factor resizes less often at the cost of memory. The
+	// default is 2.
+	GrowthFactor float64
+	// MinSize is the smallest number of buckets of the table. The default is
+	// 8.
+	MinSize int
+	// MaxSize is the largest number of buckets of the table, past which its
+	// chains grow longer instead. The default of 0 leaves the size unlimited.
+	MaxSize int
+	// ExactSize keeps the number of buckets from being rounded up to a power
+	// of 2, so the table is sized exactly as the load factor requires.
+	ExactSize bool
+}
+
+func (p HashTablePolicy) arrayPolicy() array.Policy {
+	if p.LoadFactor < 0 {
+		panic("hash load factor must be positive")
+	} else if p.LoadFactor == 0 {
+		p.LoadFactor = 0.8
+	}
+	if p.GrowthFactor == 0 {
+		p.GrowthFactor = 2
+	} else if p.GrowthFactor <= 1 {
+		panic("hash growth factor must be greater than 1")
+	}
+	if p.MinSize < 0 {
+		panic("hash minimum size must be positive")
+	} else if p.MinSize == 0 {
+		p.MinSize = minDynamicHashSize
+	}
+	if p.MaxSize < 0 || (p.MaxSize != 0 && p.MaxSize < p.MinSize) {
+		panic("hash maximum size must not be less than the minimum size")
+	}
+
+	return array.Policy{
+		LoadFactor:   p.LoadFactor,
+		GrowthFactor: p.GrowthFactor,
+		MinSize:      p.MinSize,
+		MaxSize:      p.MaxSize,
+		ExactSize:    p.ExactSize,
+	}
+}
@@ -0,0 +1,33 @@
+package embedded
+
+import (
+	"time"
+)
+
+// HashTableStats describes the state of a hash table. During an incremental
+// resize, the buckets of both the old and the new table are counted.
+type HashTableStats struct {
+	Count     int
+	TableSize int
+	TableUsed int
+	// LoadFactor is the average number of items per bucket.
+	LoadFactor float64
+	// ChainLengths is a histogram of the buckets by the number of items
+	// chained in them, so ChainLengths[0] is the number of empty buckets. It
+	// is gathered by walking the whole table.
+	ChainLengths   []int
+	MaxChainLength int
+	// ResizeCount is the number of times the table has been resized, and
+	// ResizeTime is the time spent doing so. The migration of an incremental
+	// resize is spread over the following inserts and removals, so it is not
+	// timed; MigrateSteps counts the inserts and removals which migrated
+	// buckets instead.
+	ResizeCount  int
+	ResizeTime   time.Duration
+	MigrateSteps int
+	// ReseedCount is the number of times a seeded hash map or hash list map
+	// has picked a new seed and rehashed its items in place, and ReseedTime is
+	// the time spent doing so.
+	ReseedCount int
+	ReseedTime  time.Duration
+}
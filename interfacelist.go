@@ -0,0 +1,220 @@
+package embedded
+
+// This is a double-linked list container for items of any type implementing a
+// common interface I - it allows for linear iteration over its contents,
+// yielding the interface values. As the items do not share a layout, the
+// container finds the link of an item through the getLink function given to
+// it, which usually calls a method of I returning the embedded link.
+// Iteration returns the zero value of I (nil for interface types) past either
+// end of the list.
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+type InterfaceList[I any] interface {
+	First() I
+	Last() I
+	Next(cur I) I
+	Prev(cur I) I
+	Position(index int) I
+
+	Remove(obj I) I
+	RemoveFirst() I
+	RemoveLast() I
+	RemoveAll()
+
+	InsertFirst(cur I) I
+	InsertLast(cur I) I
+	InsertAfter(prev, cur I) I
+	InsertBefore(after, cur I) I
+
+	MoveFirst(cur I)
+	MoveLast(cur I)
+	MoveAfter(dest, cur I)
+	MoveBefore(dest, cur I)
+
+	Count() int
+	IsEmpty() bool
+	IsContained(cur I) bool
+}
+
+func NewInterfaceList[I any](getLink func(obj I) *InterfaceListLink[I]) InterfaceList[I] {
+	return &embeddedInterfaceList[I]{
+		getLink: getLink,
+	}
+}
+
+type embeddedInterfaceList[I any] struct {
+	head    *InterfaceListLink[I]
+	tail    *InterfaceListLink[I]
+	count   int
+	getLink func(obj I) *InterfaceListLink[I]
+}
+
+func (c *embeddedInterfaceList[I]) valueOf(link *InterfaceListLink[I]) I {
+	if link == nil {
+		var empty I
+		return empty
+	}
+	return link.value
+}
+
+// linkOf returns the link of obj, or nil if obj is a nil interface value.
+func (c *embeddedInterfaceList[I]) linkOf(obj I) *InterfaceListLink[I] {
+	if any(obj) == nil {
+		return nil
+	}
+	return c.getLink(obj)
+}
+
+func (c *embeddedInterfaceList[I]) First() I {
+	return c.valueOf(c.head)
+}
+
+func (c *embeddedInterfaceList[I]) Last() I {
+	return c.valueOf(c.tail)
+}
+
+func (c *embeddedInterfaceList[I]) Next(cur I) I {
+	return c.valueOf(c.getLink(cur).next)
+}
+
+func (c *embeddedInterfaceList[I]) Prev(cur I) I {
+	return c.valueOf(c.getLink(cur).prev)
+}
+
+func (c *embeddedInterfaceList[I]) Position(index int) I {
+	cur := c.head
+	for cur != nil && index > 0 {
+		cur = cur.next
+		index--
+	}
+	return c.valueOf(cur)
+}
+
+func (c *embeddedInterfaceList[I]) Count() int {
+	return c.count
+}
+
+func (c *embeddedInterfaceList[I]) Remove(obj I) I {
+	objLink := c.getLink(obj)
+	if !c.isLinkContained(objLink) {
+		return obj
+	}
+
+	if objLink.prev == nil {
+		c.head = objLink.next
+	} else {
+		objLink.prev.next = objLink.next
+	}
+	if objLink.next == nil {
+		c.tail = objLink.prev
+	} else {
+		objLink.next.prev = objLink.prev
+	}
+
+	var empty I
+	objLink.prev = nil
+	objLink.next = nil
+	objLink.value = empty
+	c.count--
+	return obj
+}
+
+func (c *embeddedInterfaceList[I]) RemoveFirst() I {
+	if c.head == nil {
+		var empty I
+		return empty
+	}
+	return c.Remove(c.head.value)
+}
+
+func (c *embeddedInterfaceList[I]) RemoveLast() I {
+	if c.tail == nil {
+		var empty I
+		return empty
+	}
+	return c.Remove(c.tail.value)
+}
+
+func (c *embeddedInterfaceList[I]) RemoveAll() {
+	for c.tail != nil {
+		c.Remove(c.tail.value)
+	}
+}
+
+func (c *embeddedInterfaceList[I]) InsertFirst(cur I) I {
+	return c.insert(nil, c.head, cur)
+}
+
+func (c *embeddedInterfaceList[I]) InsertLast(cur I) I {
+	return c.insert(c.tail, nil, cur)
+}
+
+func (c *embeddedInterfaceList[I]) InsertAfter(prev, cur I) I {
+	prevLink := c.linkOf(prev)
+	if prevLink == nil {
+		return c.InsertFirst(cur)
+	}
+	return c.insert(prevLink, prevLink.next, cur)
+}
+
+func (c *embeddedInterfaceList[I]) InsertBefore(after, cur I) I {
+	afterLink := c.linkOf(after)
+	if afterLink == nil {
+		return c.InsertLast(cur)
+	}
+	return c.insert(afterLink.prev, afterLink, cur)
+}
+
+// insert links cur between the neighboring links prev and next, either of
+// which is nil at the ends of the list.
+func (c *embeddedInterfaceList[I]) insert(prev, next *InterfaceListLink[I], cur I) I {
+	curLink := c.getLink(cur)
+	curLink.prev = prev
+	curLink.next = next
+	curLink.value = cur
+	if prev == nil {
+		c.head = curLink
+	} else {
+		prev.next = curLink
+	}
+	if next == nil {
+		c.tail = curLink
+	} else {
+		next.prev = curLink
+	}
+	c.count++
+	return cur
+}
+
+func (c *embeddedInterfaceList[I]) MoveFirst(cur I) {
+	c.Remove(cur)
+	c.InsertFirst(cur)
+}
+
+func (c *embeddedInterfaceList[I]) MoveLast(cur I) {
+	c.Remove(cur)
+	c.InsertLast(cur)
+}
+
+func (c *embeddedInterfaceList[I]) MoveAfter(dest, cur I) {
+	c.Remove(cur)
+	c.InsertAfter(dest, cur)
+}
+
+func (c *embeddedInterfaceList[I]) MoveBefore(dest, cur I) {
+	c.Remove(cur)
+	c.InsertBefore(dest, cur)
+}
+
+func (c *embeddedInterfaceList[I]) IsEmpty() bool {
+	return c.count == 0
+}
+
+func (c *embeddedInterfaceList[I]) IsContained(cur I) bool {
+	return c.isLinkContained(c.getLink(cur))
+}
+
+func (c *embeddedInterfaceList[I]) isLinkContained(link *InterfaceListLink[I]) bool {
+	return link.prev != nil || c.head == link
+}
@@ -0,0 +1,121 @@
+package embedded_test
+
+import (
+	"testing"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type interfaceListEvent interface {
+	Data() int
+	EventLink() *embedded.InterfaceListLink[interfaceListEvent]
+}
+
+type interfaceListTimerEvent struct {
+	data int
+	link embedded.InterfaceListLink[interfaceListEvent]
+}
+
+func (e *interfaceListTimerEvent) Data() int {
+	return e.data
+}
+
+func (e *interfaceListTimerEvent) EventLink() *embedded.InterfaceListLink[interfaceListEvent] {
+	return &e.link
+}
+
+type interfaceListInputEvent struct {
+	key  byte
+	link embedded.InterfaceListLink[interfaceListEvent]
+	data int
+}
+
+func (e *interfaceListInputEvent) Data() int {
+	return e.data
+}
+
+func (e *interfaceListInputEvent) EventLink() *embedded.InterfaceListLink[interfaceListEvent] {
+	return &e.link
+}
+
+func newInterfaceListEventList() embedded.InterfaceList[interfaceListEvent] {
+	return embedded.NewInterfaceList(func(obj interfaceListEvent) *embedded.InterfaceListLink[interfaceListEvent] {
+		return obj.EventLink()
+	})
+}
+
+func newInterfaceListEvent(i int) interfaceListEvent {
+	if i%2 == 0 {
+		return &interfaceListTimerEvent{data: i}
+	}
+	return &interfaceListInputEvent{key: byte(i), data: i}
+}
+
+func TestEmbeddedInterfaceList(t *testing.T) {
+	const testSize = 5500
+	c := newInterfaceListEventList()
+	if c.First() != nil || c.Last() != nil || c.RemoveFirst() != nil {
+		t.Fatal("new embedded list is not empty")
+	}
+	for i := 0; i < testSize; i++ {
+		c.InsertLast(newInterfaceListEvent(i))
+	}
+	if actualCount := c.Count(); actualCount != testSize {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize)
+	}
+
+	expected := 0
+	for cur := c.First(); cur != nil; cur = c.Next(cur) {
+		if cur.Data() != expected {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.Data(), expected)
+		}
+		if _, isTimer := cur.(*interfaceListTimerEvent); isTimer != (expected%2 == 0) {
+			t.Fatal("item of unexpected type in embedded list")
+		}
+		expected++
+	}
+
+	// remove every item of one type
+	for cur := c.First(); cur != nil; {
+		next := c.Next(cur)
+		if _, isInput := cur.(*interfaceListInputEvent); isInput {
+			c.Remove(cur)
+			if c.IsContained(cur) {
+				t.Fatal("embedded list reports that removed item is present")
+			}
+		}
+		cur = next
+	}
+	expected = testSize - 2
+	for cur := c.Last(); cur != nil; cur = c.Prev(cur) {
+		if cur.Data() != expected {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.Data(), expected)
+		}
+		expected -= 2
+	}
+
+	input := newInterfaceListEvent(-1)
+	c.InsertAfter(c.First(), input)
+	c.MoveBefore(c.First(), input)
+	c.InsertBefore(nil, newInterfaceListEvent(testSize+1))
+	if c.First() != input || c.Position(1).Data() != 0 || c.Last().Data() != testSize+1 {
+		t.Fatal("items not found at expected positions")
+	}
+	c.MoveLast(input)
+	if actualLast := c.RemoveLast(); actualLast != input {
+		t.Fatal("unexpected item at end of embedded list")
+	}
+
+	c.RemoveAll()
+	if !c.IsEmpty() || c.Count() != 0 || c.Position(0) != nil {
+		t.Fatal("embedded list is not empty after RemoveAll")
+	}
+}
+
+func BenchmarkEmbeddedInterfaceList_InsertLast(b *testing.B) {
+	list := newInterfaceListEventList()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		list.InsertLast(newInterfaceListEvent(i))
+	}
+}
@@ -0,0 +1,10 @@
+package embedded
+
+// InterfaceListLink is a link to the interface list container. Its neighbors
+// are other links, and it holds the interface value of its item, so items of
+// different types can be linked together.
+type InterfaceListLink[I any] struct {
+	prev  *InterfaceListLink[I]
+	next  *InterfaceListLink[I]
+	value I
+}
@@ -3,6 +3,9 @@ package array
 type Array[T any] interface {
 	IsStatic() bool
 	Reserve(count int)
+	Shrink(count int)
+	Compact(count int)
+	Resize(size int)
 	Size() int
 	Slice() []T
 }
@@ -1,17 +1,32 @@
 package array
 
+// Policy controls how a dynamic array is sized for a count of items.
+type Policy struct {
+	// LoadFactor is the largest count reserved per element of the array.
+	LoadFactor float64
+	// GrowthFactor is the least factor by which the array grows.
+	GrowthFactor float64
+	MinSize      int
+	// MaxSize limits the size of the array, unless it is 0.
+	MaxSize int
+	// ExactSize keeps the size from being rounded up to a power of 2.
+	ExactSize bool
+}
+
 type dynamicArray[T any] struct {
 	data     []T
-	minSize  int
+	policy   Policy
+	scale    float64
 	onResize func(dest, src []T)
 }
 
-func NewDynamicArray[T any](minSize int, onResize func(dest, src []T)) Array[T] {
+func NewDynamicArray[T any](policy Policy, onResize func(dest, src []T)) Array[T] {
 	a := &dynamicArray[T]{
-		minSize:  minSize,
+		policy:   policy,
+		scale:    1 / policy.LoadFactor,
 		onResize: onResize,
 	}
-	a.Reserve(minSize)
+	a.Reserve(policy.MinSize)
 	return a
 }
 
@@ -20,12 +35,43 @@ func (a *dynamicArray[T]) IsStatic() bool {
 }
 
 func (a *dynamicArray[T]) Reserve(count int) {
-	count += count >> 2
-	if count > len(a.data) {
-		a.resize(count)
+	need := a.need(count)
+	if need <= len(a.data) {
+		return
+	}
+	if grown := int(float64(len(a.data)) * a.policy.GrowthFactor); grown > need {
+		need = grown
+	}
+	if size := a.sizeFor(need); size > len(a.data) {
+		a.resize(size)
+	}
+}
+
+// Shrink reduces the size of the array once count fills no more than a
+// quarter of what it is reserved for. The array is left with room for twice
+// count, so neither growing nor shrinking again follows soon after.
+func (a *dynamicArray[T]) Shrink(count int) {
+	need := a.need(count)
+	if need*4 > len(a.data) {
+		return
+	}
+	if size := a.sizeFor(need * 2); size < len(a.data) {
+		a.resize(size)
+	}
+}
+
+// Compact reduces the size of the array to the smallest one reserved for
+// count.
+func (a *dynamicArray[T]) Compact(count int) {
+	if size := a.sizeFor(a.need(count)); size < len(a.data) {
+		a.resize(size)
 	}
 }
 
+func (a *dynamicArray[T]) Resize(size int) {
+	a.resize(a.sizeFor(size))
+}
+
 func (a *dynamicArray[T]) Size() int {
 	return len(a.data)
 }
@@ -34,14 +80,28 @@ func (a *dynamicArray[T]) Slice() []T {
 	return a.data
 }
 
-func (a *dynamicArray[T]) resize(count int) {
-	dynamicTableOld := a.data
+// need returns the size of the array reserved for count.
+func (a *dynamicArray[T]) need(count int) int {
+	return int(float64(count) * a.scale)
+}
 
-	dynamicSize := int(nextPowerOf2(uint(count)))
-	if dynamicSize < a.minSize {
-		dynamicSize = a.minSize
+func (a *dynamicArray[T]) sizeFor(size int) int {
+	if !a.policy.ExactSize {
+		size = int(nextPowerOf2(uint(size)))
+	}
+	if size < a.policy.MinSize {
+		size = a.policy.MinSize
 	}
-	a.data = make([]T, dynamicSize)
+	if a.policy.MaxSize != 0 && size > a.policy.MaxSize {
+		size = a.policy.MaxSize
+	}
+	return size
+}
+
+func (a *dynamicArray[T]) resize(size int) {
+	dynamicTableOld := a.data
+
+	a.data = make([]T, size)
 	if a.onResize != nil {
 		a.onResize(a.data, dynamicTableOld)
 	}
@@ -7,5 +7,6 @@ func nextPowerOf2(v uint) uint {
 	v |= v >> 4
 	v |= v >> 8
 	v |= v >> 16
+	v |= v >> 32
 	return v + 1
 }
@@ -1,12 +1,14 @@
 package array
 
 type staticArray[T any] struct {
-	data []T
+	data     []T
+	onResize func(dest, src []T)
 }
 
-func NewStaticArray[T any](arraySize int) Array[T] {
+func NewStaticArray[T any](arraySize int, onResize func(dest, src []T)) Array[T] {
 	return &staticArray[T]{
-		data: make([]T, arraySize),
+		data:     make([]T, arraySize),
+		onResize: onResize,
 	}
 }
 
@@ -18,6 +20,22 @@ func (a *staticArray[T]) Reserve(count int) {
 	// do nothing
 }
 
+func (a *staticArray[T]) Shrink(count int) {
+	// do nothing
+}
+
+func (a *staticArray[T]) Compact(count int) {
+	// do nothing
+}
+
+func (a *staticArray[T]) Resize(size int) {
+	staticTableOld := a.data
+	a.data = make([]T, size)
+	if a.onResize != nil {
+		a.onResize(a.data, staticTableOld)
+	}
+}
+
 func (a *staticArray[T]) Size() int {
 	return len(a.data)
 }
@@ -7,7 +7,10 @@ type TableInterface interface {
 
 	GetTableSize() int
 	GetTableUsed() int
+	Stats() HashTableStats
 	Reserve(count int)
+	Compact()
+	Resize(tableSize int)
 	IsEmpty() bool
 }
 
@@ -27,6 +27,18 @@ type List[T any] interface {
 	MoveAfter(dest, cur *T)
 	MoveBefore(dest, cur *T)
 
+	SpliceAfter(dest *T, src List[T], first, last *T)
+	SpliceAfterCount(dest *T, src List[T], first, last *T, count int)
+	SpliceAll(dest *T, src List[T])
+
+	Sort(less func(a, b *T) bool)
+	MergeSorted(other List[T], less func(a, b *T) bool)
+
+	Reverse()
+	Rotate(n int)
+	RotateTo(obj *T)
+	SplitAfter(obj *T) List[T]
+
 	Count() int
 	IsEmpty() bool
 	IsContained(cur *T) bool
@@ -198,3 +210,259 @@ func (c *embeddedList[T]) IsEmpty() bool {
 func (c *embeddedList[T]) IsContained(cur *T) bool {
 	return c.getLink(cur).isContained(c.linkField, c.head)
 }
+
+// SpliceAfter moves the items from first through last (inclusive) out of
+// src and into this list after dest, or at the front when dest is nil.
+// The moved items are counted, so this is O(k) for k items moved; dest
+// must not be one of the items being moved.
+func (c *embeddedList[T]) SpliceAfter(dest *T, src List[T], first, last *T) {
+	count := 1
+	for cur := first; cur != last; cur = c.getLink(cur).next {
+		count++
+	}
+	c.SpliceAfterCount(dest, src, first, last, count)
+}
+
+// SpliceAfterCount is SpliceAfter for a caller that already knows how
+// many items are being moved, and is O(1).
+func (c *embeddedList[T]) SpliceAfterCount(dest *T, src List[T], first, last *T, count int) {
+	s := c.spliceSource(src)
+	firstLink := c.getLink(first)
+	lastLink := c.getLink(last)
+	if firstLink.prev == nil {
+		s.head = lastLink.next
+	} else {
+		c.getLink(firstLink.prev).next = lastLink.next
+	}
+	if lastLink.next == nil {
+		s.tail = firstLink.prev
+	} else {
+		c.getLink(lastLink.next).prev = firstLink.prev
+	}
+	s.count -= count
+
+	c.splice(dest, first, last, count)
+}
+
+// SpliceAll moves all items out of src and into this list after dest, or
+// at the front when dest is nil, and is O(1).
+func (c *embeddedList[T]) SpliceAll(dest *T, src List[T]) {
+	s := c.spliceSource(src)
+	if s == c {
+		panic("cannot splice a list into itself")
+	}
+	if s.head == nil {
+		return
+	}
+
+	first, last, count := s.head, s.tail, s.count
+	s.head = nil
+	s.tail = nil
+	s.count = 0
+
+	c.splice(dest, first, last, count)
+}
+
+func (c *embeddedList[T]) spliceSource(src List[T]) *embeddedList[T] {
+	s, ok := src.(*embeddedList[T])
+	if !ok || s.linkField != c.linkField {
+		panic("cannot splice between lists using different links")
+	}
+	return s
+}
+
+// splice links the chain of count items from first through last, which must
+// already be cut out of its list, in after dest.
+func (c *embeddedList[T]) splice(dest, first, last *T, count int) {
+	var next *T
+	if dest == nil {
+		next = c.head
+		c.head = first
+	} else {
+		destLink := c.getLink(dest)
+		next = destLink.next
+		destLink.next = first
+	}
+	c.getLink(first).prev = dest
+
+	c.getLink(last).next = next
+	if next == nil {
+		c.tail = last
+	} else {
+		c.getLink(next).prev = last
+	}
+	c.count += count
+}
+
+// Sort reorders the list with a stable merge sort, relinking the items in
+// place without allocating.
+func (c *embeddedList[T]) Sort(less func(a, b *T) bool) {
+	// bins[i] is either empty or holds a sorted chain of 2^i items, with the
+	// items of higher bins preceding those of lower ones in the original order
+	var bins [64]*T
+	cur := c.head
+	for cur != nil {
+		curLink := c.getLink(cur)
+		next := curLink.next
+		curLink.next = nil
+
+		chain := cur
+		i := 0
+		for ; bins[i] != nil; i++ {
+			chain = c.merge(bins[i], chain, less)
+			bins[i] = nil
+		}
+		bins[i] = chain
+		cur = next
+	}
+
+	var head *T
+	for _, chain := range bins {
+		if chain != nil {
+			head = c.merge(chain, head, less)
+		}
+	}
+	c.relink(head)
+}
+
+// MergeSorted moves all items out of other, merging them into this list.
+// Both lists must already be sorted by less; items of this list are
+// placed ahead of equal items of other.
+func (c *embeddedList[T]) MergeSorted(other List[T], less func(a, b *T) bool) {
+	s := c.spliceSource(other)
+	if s == c {
+		panic("cannot merge a list into itself")
+	}
+	if s.head == nil {
+		return
+	}
+	head := c.merge(c.head, s.head, less)
+	c.count += s.count
+	s.head = nil
+	s.tail = nil
+	s.count = 0
+	c.relink(head)
+}
+
+// merge joins two sorted chains, linked only through next, into one sorted
+// chain. Items of a are placed ahead of equal items of b.
+func (c *embeddedList[T]) merge(a, b *T, less func(a, b *T) bool) *T {
+	var head *T
+	tail := &head
+	for a != nil && b != nil {
+		if less(b, a) {
+			*tail = b
+			tail = &c.getLink(b).next
+			b = *tail
+		} else {
+			*tail = a
+			tail = &c.getLink(a).next
+			a = *tail
+		}
+	}
+	if a != nil {
+		*tail = a
+	} else {
+		*tail = b
+	}
+	return head
+}
+
+// relink makes the chain starting at head, linked only through next, the
+// contents of the list, restoring the prev links and the tail.
+func (c *embeddedList[T]) relink(head *T) {
+	c.head = head
+	var prev *T
+	for cur := head; cur != nil; cur = c.getLink(cur).next {
+		c.getLink(cur).prev = prev
+		prev = cur
+	}
+	c.tail = prev
+}
+
+func (c *embeddedList[T]) Reverse() {
+	for cur := c.head; cur != nil; {
+		curLink := c.getLink(cur)
+		next := curLink.next
+		curLink.next, curLink.prev = curLink.prev, curLink.next
+		cur = next
+	}
+	c.head, c.tail = c.tail, c.head
+}
+
+// Rotate moves the first n items to the end of the list, so the item at
+// position n becomes the first; a negative n rotates the other way.
+func (c *embeddedList[T]) Rotate(n int) {
+	if c.count < 2 {
+		return
+	}
+	n %= c.count
+	if n < 0 {
+		n += c.count
+	}
+	if n == 0 {
+		return
+	}
+
+	var first *T
+	if n <= c.count/2 {
+		first = c.Position(n)
+	} else {
+		first = c.tail
+		for i := c.count - 1; i > n; i-- {
+			first = c.getLink(first).prev
+		}
+	}
+	c.RotateTo(first)
+}
+
+// RotateTo makes obj the first item, keeping the circular order.
+func (c *embeddedList[T]) RotateTo(obj *T) {
+	if obj == c.head {
+		return
+	}
+
+	objLink := c.getLink(obj)
+	c.getLink(c.tail).next = c.head
+	c.getLink(c.head).prev = c.tail
+	c.getLink(objLink.prev).next = nil
+	c.tail = objLink.prev
+	objLink.prev = nil
+	c.head = obj
+}
+
+// SplitAfter moves the items after obj into a new list, which is
+// returned. If obj is nil, all items are moved.
+func (c *embeddedList[T]) SplitAfter(obj *T) List[T] {
+	other := &embeddedList[T]{
+		linkField: c.linkField,
+	}
+
+	first := c.head
+	if obj != nil {
+		first = c.getLink(obj).next
+	}
+	if first == nil {
+		return other
+	}
+
+	count := 0
+	for cur := first; cur != nil; cur = c.getLink(cur).next {
+		count++
+	}
+
+	other.head = first
+	other.tail = c.tail
+	other.count = count
+	c.getLink(first).prev = nil
+
+	if obj == nil {
+		c.head = nil
+		c.tail = nil
+	} else {
+		c.getLink(obj).next = nil
+		c.tail = obj
+	}
+	c.count -= count
+	return other
+}
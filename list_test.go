@@ -1,6 +1,8 @@
 package embedded_test
 
 import (
+	"math/rand"
+	"sort"
 	"testing"
 	"unsafe"
 
@@ -21,6 +23,178 @@ func TestEmbeddedList(t *testing.T) {
 	testEmbeddedList(t, c, testSize, removeTarget)
 }
 
+func TestEmbeddedListSplice(t *testing.T) {
+	const testSize = 10
+	a := embedded.NewList[listEntry](listEntryLinkField)
+	b := embedded.NewList[listEntry](listEntryLinkField)
+	for i := 0; i < testSize; i++ {
+		a.InsertLast(&listEntry{data: i})
+		b.InsertLast(&listEntry{data: testSize + i})
+	}
+
+	// move 3..5 of a after the first item of b
+	b.SpliceAfter(b.First(), a, a.Position(3), a.Position(5))
+	testEmbeddedListContents(t, a, []int{0, 1, 2, 6, 7, 8, 9})
+	testEmbeddedListContents(t, b, []int{10, 3, 4, 5, 11, 12, 13, 14, 15, 16, 17, 18, 19})
+
+	b.SpliceAfter(nil, a, a.First(), a.First())
+	b.SpliceAfter(b.First(), a, a.Position(2), a.Last())
+	testEmbeddedListContents(t, a, []int{1, 2})
+	testEmbeddedListContents(t, b, []int{0, 6, 7, 8, 9, 10, 3, 4, 5, 11, 12, 13, 14, 15, 16, 17, 18, 19})
+
+	b.SpliceAfterCount(nil, a, a.First(), a.First(), 1)
+	testEmbeddedListContents(t, a, []int{2})
+
+	// move a run within the same list
+	b.SpliceAfter(b.Last(), b, b.Position(1), b.Position(3))
+	testEmbeddedListContents(t, b, []int{1, 8, 9, 10, 3, 4, 5, 11, 12, 13, 14, 15, 16, 17, 18, 19, 0, 6, 7})
+
+	a.SpliceAll(nil, b)
+	testEmbeddedListContents(t, a, []int{1, 8, 9, 10, 3, 4, 5, 11, 12, 13, 14, 15, 16, 17, 18, 19, 0, 6, 7, 2})
+	testEmbeddedListContents(t, b, nil)
+
+	b.SpliceAll(nil, a)
+	b.SpliceAll(b.Last(), a)
+	testEmbeddedListContents(t, a, nil)
+	if actualCount := b.Count(); actualCount != testSize*2 {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize*2)
+	}
+}
+
+func TestEmbeddedListSort(t *testing.T) {
+	const testSize = 5500
+	c := embedded.NewList[listEntry](listEntryLinkField)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < testSize; i++ {
+		c.InsertLast(&listEntry{data: r.Intn(testSize / 10)})
+	}
+	var expected []int
+	for cur := c.First(); cur != nil; cur = c.Next(cur) {
+		expected = append(expected, cur.data)
+	}
+	sort.Ints(expected)
+
+	// entries are stamped with their original order to check stability
+	order := make(map[*listEntry]int)
+	for i, cur := 0, c.First(); cur != nil; i, cur = i+1, c.Next(cur) {
+		order[cur] = i
+	}
+
+	c.Sort(func(a, b *listEntry) bool {
+		return a.data < b.data
+	})
+	testEmbeddedListContents(t, c, expected)
+	for cur := c.First(); c.Next(cur) != nil; cur = c.Next(cur) {
+		if next := c.Next(cur); cur.data == next.data && order[cur] > order[next] {
+			t.Fatal("sort is not stable")
+		}
+	}
+
+	other := embedded.NewList[listEntry](listEntryLinkField)
+	for i := 0; i < testSize/10; i += 3 {
+		other.InsertLast(&listEntry{data: i})
+		expected = append(expected, i)
+	}
+	sort.Ints(expected)
+	c.MergeSorted(other, func(a, b *listEntry) bool {
+		return a.data < b.data
+	})
+	testEmbeddedListContents(t, c, expected)
+	testEmbeddedListContents(t, other, nil)
+	for cur := c.First(); c.Next(cur) != nil; cur = c.Next(cur) {
+		next := c.Next(cur)
+		_, curFromList := order[cur]
+		_, nextFromList := order[next]
+		if cur.data == next.data && !curFromList && nextFromList {
+			t.Fatal("merge placed an item of other ahead of an equal item")
+		}
+	}
+
+	empty := embedded.NewList[listEntry](listEntryLinkField)
+	empty.Sort(func(a, b *listEntry) bool {
+		return a.data < b.data
+	})
+	testEmbeddedListContents(t, empty, nil)
+}
+
+func TestEmbeddedListRestructure(t *testing.T) {
+	c := embedded.NewList[listEntry](listEntryLinkField)
+	for i := 0; i < 6; i++ {
+		c.InsertLast(&listEntry{data: i})
+	}
+
+	c.Reverse()
+	testEmbeddedListContents(t, c, []int{5, 4, 3, 2, 1, 0})
+	c.Reverse()
+	testEmbeddedListContents(t, c, []int{0, 1, 2, 3, 4, 5})
+
+	c.Rotate(2)
+	testEmbeddedListContents(t, c, []int{2, 3, 4, 5, 0, 1})
+	c.Rotate(-1)
+	testEmbeddedListContents(t, c, []int{1, 2, 3, 4, 5, 0})
+	c.Rotate(5)
+	testEmbeddedListContents(t, c, []int{0, 1, 2, 3, 4, 5})
+	c.Rotate(13)
+	testEmbeddedListContents(t, c, []int{1, 2, 3, 4, 5, 0})
+	c.Rotate(6)
+	testEmbeddedListContents(t, c, []int{1, 2, 3, 4, 5, 0})
+
+	c.RotateTo(c.Last())
+	testEmbeddedListContents(t, c, []int{0, 1, 2, 3, 4, 5})
+	c.RotateTo(c.First())
+	testEmbeddedListContents(t, c, []int{0, 1, 2, 3, 4, 5})
+	c.RotateTo(c.Position(3))
+	testEmbeddedListContents(t, c, []int{3, 4, 5, 0, 1, 2})
+
+	tail := c.SplitAfter(c.Position(2))
+	testEmbeddedListContents(t, c, []int{3, 4, 5})
+	testEmbeddedListContents(t, tail, []int{0, 1, 2})
+
+	empty := c.SplitAfter(c.Last())
+	testEmbeddedListContents(t, empty, nil)
+	testEmbeddedListContents(t, c, []int{3, 4, 5})
+
+	all := c.SplitAfter(nil)
+	testEmbeddedListContents(t, c, nil)
+	testEmbeddedListContents(t, all, []int{3, 4, 5})
+
+	c.Reverse()
+	c.Rotate(1)
+	testEmbeddedListContents(t, c, nil)
+	all.InsertLast(&listEntry{data: 6})
+	testEmbeddedListContents(t, all, []int{3, 4, 5, 6})
+}
+
+func testEmbeddedListContents(t *testing.T, c embedded.List[listEntry], expected []int) {
+	t.Helper()
+	if actualCount := c.Count(); actualCount != len(expected) {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, len(expected))
+	}
+	cur := c.First()
+	for _, data := range expected {
+		if cur == nil || cur.data != data {
+			t.Fatal("expected entry not found")
+		}
+		if !c.IsContained(cur) {
+			t.Fatal("embedded list reports that contained item is not present")
+		}
+		cur = c.Next(cur)
+	}
+	if cur != nil {
+		t.Fatal("unexpected entry after last")
+	}
+	cur = c.Last()
+	for i := len(expected) - 1; i >= 0; i-- {
+		if cur == nil || cur.data != expected[i] {
+			t.Fatal("expected entry not found")
+		}
+		cur = c.Prev(cur)
+	}
+	if cur != nil {
+		t.Fatal("unexpected entry before first")
+	}
+}
+
 func BenchmarkEmbeddedList_InsertLast(b *testing.B) {
 	list := embedded.NewList[listEntry](listEntryLinkField)
 	b.ReportAllocs()
@@ -124,3 +298,25 @@ func testEmbeddedList(t *testing.T, c embedded.List[listEntry], testSize int, re
 		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, 0)
 	}
 }
+
+func BenchmarkEmbeddedList_Sort(b *testing.B) {
+	const listSize = 1 << 16
+	list := embedded.NewList[listEntry](listEntryLinkField)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < listSize; i++ {
+		list.InsertLast(&listEntry{data: r.Int()})
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%2 == 0 {
+			list.Sort(func(a, b *listEntry) bool {
+				return a.data < b.data
+			})
+		} else {
+			list.Sort(func(a, b *listEntry) bool {
+				return a.data > b.data
+			})
+		}
+	}
+}
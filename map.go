@@ -1,6 +1,8 @@
 package embedded
 
 import (
+	"unsafe"
+
 	"golang.org/x/exp/constraints"
 )
 
@@ -42,28 +44,100 @@ type MapKeyType interface {
 }
 
 func NewMap[TKey MapKeyType, T any](linkField uintptr) Map[TKey, T] {
-	return &embeddedMap[TKey, T]{
-		linkField: linkField,
+	var ml MapLink[TKey, T]
+	return &embeddedMap[TKey, T, uint]{
+		nodeField: linkField + unsafe.Offsetof(ml.node),
+		metaField: linkField + unsafe.Offsetof(ml.position),
+		redField:  linkField + unsafe.Offsetof(ml.red),
+		keyField:  linkField + unsafe.Offsetof(ml.key),
+	}
+}
+
+// embeddedMap is the red-black tree behind the map containers. The key of an
+// item is read from the field at keyField, or through keyOf when it is set.
+// The subtree size of a node is kept in the field at metaField as an S. The
+// color of a node is a bool in the field at redField, or, when packed is set,
+// the low bit of its subtree size. The tree holds at most maxCount items when
+// it is set.
+type embeddedMap[TKey MapKeyType, T any, S MapCompactSizeType] struct {
+	root      *T
+	count     int
+	nodeField uintptr
+	metaField uintptr
+	redField  uintptr
+	keyField  uintptr
+	keyOf     func(obj *T) TKey
+	packed    bool
+	maxCount  int
+}
+
+func (c *embeddedMap[TKey, T, S]) getLink(obj *T) *mapNode[T] {
+	return getMapNode(obj, c.nodeField)
+}
+
+func (c *embeddedMap[TKey, T, S]) getMeta(link *mapNode[T]) *S {
+	return (*S)(unsafe.Add(unsafe.Pointer(link), int(c.metaField)-int(c.nodeField)))
+}
+
+func (c *embeddedMap[TKey, T, S]) getRed(link *mapNode[T]) *bool {
+	return (*bool)(unsafe.Add(unsafe.Pointer(link), int(c.redField)-int(c.nodeField)))
+}
+
+func (c *embeddedMap[TKey, T, S]) isRed(link *mapNode[T]) bool {
+	if !c.packed {
+		return *c.getRed(link)
 	}
+	return *c.getMeta(link)&mapRedBit != 0
 }
 
-type embeddedMap[TKey MapKeyType, T any] struct {
-	root      *T
-	count     int
-	linkField uintptr
+func (c *embeddedMap[TKey, T, S]) setRed(link *mapNode[T], red bool) {
+	if !c.packed {
+		*c.getRed(link) = red
+	} else if meta := c.getMeta(link); red {
+		*meta |= mapRedBit
+	} else {
+		*meta &^= mapRedBit
+	}
+}
+
+func (c *embeddedMap[TKey, T, S]) size(link *mapNode[T]) int {
+	if !c.packed {
+		return int(*c.getMeta(link))
+	}
+	return int(*c.getMeta(link) >> 1)
+}
+
+func (c *embeddedMap[TKey, T, S]) setSize(link *mapNode[T], size int) {
+	if meta := c.getMeta(link); !c.packed {
+		*meta = S(size)
+	} else {
+		*meta = S(size)<<1 | *meta&mapRedBit
+	}
+}
+
+func (c *embeddedMap[TKey, T, S]) addSize(link *mapNode[T], delta int) {
+	if !c.packed {
+		*c.getMeta(link) += S(delta)
+	} else {
+		*c.getMeta(link) += S(delta) << 1
+	}
 }
 
-func (c *embeddedMap[TKey, T]) getLink(obj *T) *MapLink[TKey, T] {
-	return getMapLink[TKey](obj, c.linkField)
+func (c *embeddedMap[TKey, T, S]) getKey(obj *T) TKey {
+	if c.keyOf != nil {
+		return c.keyOf(obj)
+	}
+	return *(*TKey)(unsafe.Add(unsafe.Pointer(obj), c.keyField))
 }
 
-func (c *embeddedMap[TKey, T]) Find(key TKey) *T {
+func (c *embeddedMap[TKey, T, S]) Find(key TKey) *T {
 	walk := c.root
 	for walk != nil {
 		walkLink := c.getLink(walk)
-		if key < walkLink.key {
+		walkKey := c.getKey(walk)
+		if key < walkKey {
 			walk = walkLink.left
-		} else if walkLink.key < key {
+		} else if walkKey < key {
 			walk = walkLink.right
 		} else {
 			return walk
@@ -72,13 +146,14 @@ func (c *embeddedMap[TKey, T]) Find(key TKey) *T {
 	return nil
 }
 
-func (c *embeddedMap[TKey, T]) FindFirst(key TKey) *T {
+func (c *embeddedMap[TKey, T, S]) FindFirst(key TKey) *T {
 	walk := c.root
 	for walk != nil {
 		walkLink := c.getLink(walk)
-		if key < walkLink.key {
+		walkKey := c.getKey(walk)
+		if key < walkKey {
 			walk = walkLink.left
-		} else if walkLink.key < key {
+		} else if walkKey < key {
 			walk = walkLink.right
 		} else {
 			left := c.Prev(walk)
@@ -92,7 +167,7 @@ func (c *embeddedMap[TKey, T]) FindFirst(key TKey) *T {
 	return nil
 }
 
-func (c *embeddedMap[TKey, T]) FindNext(cur *T) *T {
+func (c *embeddedMap[TKey, T, S]) FindNext(cur *T) *T {
 	next := c.Next(cur)
 	if next != nil && c.GetKey(cur) == c.GetKey(next) {
 		return next
@@ -100,17 +175,18 @@ func (c *embeddedMap[TKey, T]) FindNext(cur *T) *T {
 	return nil
 }
 
-func (c *embeddedMap[TKey, T]) FindLowerInclusive(key TKey) *T {
+func (c *embeddedMap[TKey, T, S]) FindLowerInclusive(key TKey) *T {
 	walk := c.root
 	for walk != nil {
 		walkLink := c.getLink(walk)
-		if key < walkLink.key {
+		walkKey := c.getKey(walk)
+		if key < walkKey {
 			left := walkLink.left
 			if left == nil {
 				return c.Prev(walk)
 			}
 			walk = left
-		} else if walkLink.key < key {
+		} else if walkKey < key {
 			right := walkLink.right
 			if right == nil {
 				return walk
@@ -128,17 +204,18 @@ func (c *embeddedMap[TKey, T]) FindLowerInclusive(key TKey) *T {
 	return nil
 }
 
-func (c *embeddedMap[TKey, T]) FindUpperInclusive(key TKey) *T {
+func (c *embeddedMap[TKey, T, S]) FindUpperInclusive(key TKey) *T {
 	walk := c.root
 	for walk != nil {
 		walkLink := c.getLink(walk)
-		if key < walkLink.key {
+		walkKey := c.getKey(walk)
+		if key < walkKey {
 			left := walkLink.left
 			if left == nil {
 				return walk
 			}
 			walk = left
-		} else if walkLink.key < key {
+		} else if walkKey < key {
 			right := walkLink.right
 			if right == nil {
 				return c.Next(walk)
@@ -156,17 +233,18 @@ func (c *embeddedMap[TKey, T]) FindUpperInclusive(key TKey) *T {
 	return nil
 }
 
-func (c *embeddedMap[TKey, T]) FindLowerExclusive(key TKey) *T {
+func (c *embeddedMap[TKey, T, S]) FindLowerExclusive(key TKey) *T {
 	walk := c.root
 	for walk != nil {
 		walkLink := c.getLink(walk)
-		if key < walkLink.key {
+		walkKey := c.getKey(walk)
+		if key < walkKey {
 			left := walkLink.left
 			if left == nil {
 				return c.Prev(walk)
 			}
 			walk = left
-		} else if walkLink.key < key {
+		} else if walkKey < key {
 			right := walkLink.right
 			if right == nil {
 				return walk
@@ -189,17 +267,18 @@ func (c *embeddedMap[TKey, T]) FindLowerExclusive(key TKey) *T {
 	return nil
 }
 
-func (c *embeddedMap[TKey, T]) FindUpperExclusive(key TKey) *T {
+func (c *embeddedMap[TKey, T, S]) FindUpperExclusive(key TKey) *T {
 	walk := c.root
 	for walk != nil {
 		walkLink := c.getLink(walk)
-		if key < walkLink.key {
+		walkKey := c.getKey(walk)
+		if key < walkKey {
 			left := walkLink.left
 			if left == nil {
 				return walk
 			}
 			walk = left
-		} else if walkLink.key < key {
+		} else if walkKey < key {
 			right := walkLink.right
 			if right == nil {
 				return c.Next(walk)
@@ -222,7 +301,7 @@ func (c *embeddedMap[TKey, T]) FindUpperExclusive(key TKey) *T {
 	return nil
 }
 
-func (c *embeddedMap[TKey, T]) First() *T {
+func (c *embeddedMap[TKey, T, S]) First() *T {
 	var prev *T
 	cur := c.root
 	for cur != nil {
@@ -232,7 +311,7 @@ func (c *embeddedMap[TKey, T]) First() *T {
 	return prev
 }
 
-func (c *embeddedMap[TKey, T]) Last() *T {
+func (c *embeddedMap[TKey, T, S]) Last() *T {
 	var prev *T
 	cur := c.root
 	for cur != nil {
@@ -242,7 +321,7 @@ func (c *embeddedMap[TKey, T]) Last() *T {
 	return prev
 }
 
-func (c *embeddedMap[TKey, T]) Next(cur *T) *T {
+func (c *embeddedMap[TKey, T, S]) Next(cur *T) *T {
 	curLink := c.getLink(cur)
 	if curLink.right != nil {
 		walk := curLink.right
@@ -264,7 +343,7 @@ func (c *embeddedMap[TKey, T]) Next(cur *T) *T {
 	return curParent
 }
 
-func (c *embeddedMap[TKey, T]) Prev(cur *T) *T {
+func (c *embeddedMap[TKey, T, S]) Prev(cur *T) *T {
 	curLink := c.getLink(cur)
 	if curLink.left != nil {
 		walk := curLink.left
@@ -286,7 +365,7 @@ func (c *embeddedMap[TKey, T]) Prev(cur *T) *T {
 	return curParent
 }
 
-func (c *embeddedMap[TKey, T]) GetPosition(obj *T) int {
+func (c *embeddedMap[TKey, T, S]) GetPosition(obj *T) int {
 	walk := obj
 	prev := walk
 	position := 0
@@ -294,7 +373,7 @@ func (c *embeddedMap[TKey, T]) GetPosition(obj *T) int {
 		walkLink := c.getLink(walk)
 		if walkLink.left != prev {
 			if walkLink.left != nil {
-				position += c.getLink(walkLink.left).position
+				position += c.size(c.getLink(walkLink.left))
 			}
 			position++
 		}
@@ -304,13 +383,13 @@ func (c *embeddedMap[TKey, T]) GetPosition(obj *T) int {
 	return position - 1
 }
 
-func (c *embeddedMap[TKey, T]) Position(index int) *T {
+func (c *embeddedMap[TKey, T, S]) Position(index int) *T {
 	walk := c.root
 	walkIndex := 0
 	if walk != nil {
 		walkLink := c.getLink(walk)
 		if walkLink.left != nil {
-			walkIndex = c.getLink(walkLink.left).position
+			walkIndex = c.size(c.getLink(walkLink.left))
 		}
 	}
 	for walk != nil {
@@ -318,14 +397,18 @@ func (c *embeddedMap[TKey, T]) Position(index int) *T {
 		if index < walkIndex {
 			walk = walkLink.left
 			walkIndex--
-			if walkLink.right != nil {
-				walkIndex -= c.getLink(walkLink.right).position
+			if walk != nil {
+				if right := c.getLink(walk).right; right != nil {
+					walkIndex -= c.size(c.getLink(right))
+				}
 			}
 		} else if walkIndex < index {
 			walk = walkLink.right
 			walkIndex++
-			if walkLink.left != nil {
-				walkIndex -= c.getLink(walkLink.left).position
+			if walk != nil {
+				if left := c.getLink(walk).left; left != nil {
+					walkIndex += c.size(c.getLink(left))
+				}
 			}
 		} else {
 			return walk
@@ -334,18 +417,29 @@ func (c *embeddedMap[TKey, T]) Position(index int) *T {
 	return nil
 }
 
-func (c *embeddedMap[TKey, T]) Insert(key TKey, obj *T) *T {
+func (c *embeddedMap[TKey, T, S]) Insert(key TKey, obj *T) *T {
+	*(*TKey)(unsafe.Add(unsafe.Pointer(obj), c.keyField)) = key
+	return c.insert(obj)
+}
+
+// insert places obj in the tree by the key it already holds.
+func (c *embeddedMap[TKey, T, S]) insert(obj *T) *T {
+	if c.maxCount != 0 && c.count >= c.maxCount {
+		panic("cannot insert beyond the capacity of the map size type")
+	}
+
+	key := c.getKey(obj)
 	var parent *T
 	parentBranch := &c.root
 	walk := c.root
 	for walk != nil {
 		parent = walk
-		c.getLink(parent).position++
+		c.addSize(c.getLink(parent), 1)
 		walkLink := c.getLink(walk)
-		if key < walkLink.key {
+		if key < c.getKey(walk) {
 			parentBranch = &walkLink.left
 			walk = *parentBranch
-		} else if walkLink.key < key {
+		} else {
 			parentBranch = &walkLink.right
 			walk = *parentBranch
 		}
@@ -356,22 +450,20 @@ func (c *embeddedMap[TKey, T]) Insert(key TKey, obj *T) *T {
 	objLink.parent = parent
 	objLink.left = nil
 	objLink.right = nil
-	objLink.red = true
-	objLink.position = 1
-	objLink.key = key
+	c.setRed(objLink, true)
+	c.setSize(objLink, 1)
 	c.count++
 	c.insertFixup(obj)
 	return obj
 }
 
-func (c *embeddedMap[TKey, T]) Remove(obj *T) *T {
+func (c *embeddedMap[TKey, T, S]) Remove(obj *T) *T {
 	objLink := c.getLink(obj)
 	if objLink.left != nil && objLink.right != nil {
 		succ := c.Next(obj)
 		curParent := objLink.parent
 		curLeft := objLink.left
 		curRight := objLink.right
-		curRed := objLink.red
 		curParentChild := &c.root
 		if curParent != nil {
 			curParentLink := c.getLink(curParent)
@@ -386,20 +478,25 @@ func (c *embeddedMap[TKey, T]) Remove(obj *T) *T {
 		succParent := succLink.parent
 		succLeft := succLink.left
 		succRight := succLink.right
-		succRed := succLink.red
 		succParentLink := c.getLink(succParent)
 		succParentChild := &succParentLink.right
 		if succParentLink.left == succ {
 			succParentChild = &succParentLink.left
 		}
 
-		objLink.position, succLink.position = succLink.position, objLink.position
+		objMeta, succMeta := c.getMeta(objLink), c.getMeta(succLink)
+		*objMeta, *succMeta = *succMeta, *objMeta
+		if !c.packed {
+			objRed, succRed := c.getRed(objLink), c.getRed(succLink)
+			*objRed, *succRed = *succRed, *objRed
+		}
 
-		objLink.left, objLink.right, objLink.red = succLeft, succRight, succRed
+		objLink.left, objLink.right = succLeft, succRight
 		succLink.parent = curParent
-		succLink.left, succLink.right, succLink.red = curLeft, curRight, curRed
+		succLink.left, succLink.right = curLeft, curRight
 		objLink.parent = succ
 		*curParentChild = succ
+		c.getLink(curLeft).parent = succ
 		if succRight != nil {
 			c.getLink(succRight).parent = obj
 		}
@@ -415,7 +512,7 @@ func (c *embeddedMap[TKey, T]) Remove(obj *T) *T {
 		}
 	}
 
-	if objLink.red {
+	if c.isRed(objLink) {
 		c.cutNode(obj)
 	} else {
 		if objLink.left == nil && objLink.right == nil {
@@ -427,8 +524,8 @@ func (c *embeddedMap[TKey, T]) Remove(obj *T) *T {
 				child = objLink.right
 			}
 			childLink := c.getLink(child)
-			if childLink.red {
-				childLink.red = false
+			if c.isRed(childLink) {
+				c.setRed(childLink, false)
 				c.cutNode(obj)
 			} else {
 				c.cutNode(obj)
@@ -441,7 +538,7 @@ func (c *embeddedMap[TKey, T]) Remove(obj *T) *T {
 	return obj
 }
 
-func (c *embeddedMap[TKey, T]) RemoveFirst() *T {
+func (c *embeddedMap[TKey, T, S]) RemoveFirst() *T {
 	head := c.First()
 	if head == nil {
 		return nil
@@ -449,7 +546,7 @@ func (c *embeddedMap[TKey, T]) RemoveFirst() *T {
 	return c.Remove(head)
 }
 
-func (c *embeddedMap[TKey, T]) RemoveLast() *T {
+func (c *embeddedMap[TKey, T, S]) RemoveLast() *T {
 	tail := c.Last()
 	if tail == nil {
 		return nil
@@ -457,29 +554,29 @@ func (c *embeddedMap[TKey, T]) RemoveLast() *T {
 	return c.Remove(tail)
 }
 
-func (c *embeddedMap[TKey, T]) Move(cur *T, newKey TKey) {
+func (c *embeddedMap[TKey, T, S]) Move(cur *T, newKey TKey) {
 	c.Remove(cur)
 	c.Insert(newKey, cur)
 }
 
-func (c *embeddedMap[TKey, T]) GetKey(obj *T) TKey {
-	return c.getLink(obj).key
+func (c *embeddedMap[TKey, T, S]) GetKey(obj *T) TKey {
+	return c.getKey(obj)
 }
 
-func (c *embeddedMap[TKey, T]) Count() int {
+func (c *embeddedMap[TKey, T, S]) Count() int {
 	return c.count
 }
 
-func (c *embeddedMap[TKey, T]) IsEmpty() bool {
+func (c *embeddedMap[TKey, T, S]) IsEmpty() bool {
 	return c.count == 0
 }
 
-func (c *embeddedMap[TKey, T]) RemoveAll() {
+func (c *embeddedMap[TKey, T, S]) RemoveAll() {
 	c.root = nil
 	c.count = 0
 }
 
-func (c *embeddedMap[TKey, T]) IsContained(obj *T) bool {
+func (c *embeddedMap[TKey, T, S]) IsContained(obj *T) bool {
 	walk := c.FindFirst(c.GetKey(obj))
 	for walk != nil {
 		if walk == obj {
@@ -490,44 +587,46 @@ func (c *embeddedMap[TKey, T]) IsContained(obj *T) bool {
 	return false
 }
 
-func (c *embeddedMap[TKey, T]) insertFixup(cur *T) {
+func (c *embeddedMap[TKey, T, S]) insertFixup(cur *T) {
 	curLink := c.getLink(cur)
 	if curLink.parent == nil {
-		curLink.red = false
-	} else if parentLink := c.getLink(curLink.parent); parentLink.red {
+		c.setRed(curLink, false)
+	} else if parentLink := c.getLink(curLink.parent); c.isRed(parentLink) {
 		parent := curLink.parent
 		grand := parentLink.parent
 		grandLink := c.getLink(grand)
 		uncle := grandLink.right
-		if grandLink.left == parent {
+		if grandLink.right == parent {
 			uncle = grandLink.left
 		}
 
-		var uncleLink *MapLink[TKey, T]
+		var uncleLink *mapNode[T]
 		if uncle != nil {
 			uncleLink = c.getLink(uncle)
 		}
 
-		if uncleLink != nil && uncleLink.red {
-			parentLink.red = false
-			uncleLink.red = false
-			grandLink.red = true
+		if uncleLink != nil && c.isRed(uncleLink) {
+			c.setRed(parentLink, false)
+			c.setRed(uncleLink, false)
+			c.setRed(grandLink, true)
 			c.insertFixup(grand)
 		} else {
 			if cur == parentLink.right && parent == grandLink.left {
 				c.rotateLeft(parent)
 				cur = curLink.left
+				curLink = c.getLink(cur)
 			} else if cur == parentLink.left && parent == grandLink.right {
 				c.rotateRight(parent)
 				cur = curLink.right
+				curLink = c.getLink(cur)
 			}
 
 			parent = curLink.parent
 			parentLink = c.getLink(parent)
 			grand = parentLink.parent
 			grandLink = c.getLink(grand)
-			parentLink.red = false
-			grandLink.red = true
+			c.setRed(parentLink, false)
+			c.setRed(grandLink, true)
 			if cur == parentLink.left && parent == grandLink.left {
 				c.rotateRight(grand)
 			} else {
@@ -537,27 +636,28 @@ func (c *embeddedMap[TKey, T]) insertFixup(cur *T) {
 	}
 }
 
-func (c *embeddedMap[TKey, T]) rotateLeft(cur *T) {
+func (c *embeddedMap[TKey, T, S]) rotateLeft(cur *T) {
 	curLink := c.getLink(cur)
 	right := curLink.right
 	parent := curLink.parent
-	left := curLink.left
+
+	rightLink := c.getLink(right)
+	inner := rightLink.left
 
 	curLink.parent = right
-	curLink.right = left
+	curLink.right = inner
 
-	rightLink := c.getLink(right)
 	rightLink.parent = parent
 	rightLink.left = cur
 
-	newC := curLink.position - rightLink.position
-	if left != nil {
-		leftLink := c.getLink(left)
-		leftLink.parent = cur
-		newC += leftLink.position
+	newC := c.size(curLink) - c.size(rightLink)
+	if inner != nil {
+		innerLink := c.getLink(inner)
+		innerLink.parent = cur
+		newC += c.size(innerLink)
 	}
-	rightLink.position = curLink.position
-	curLink.position = newC
+	c.setSize(rightLink, c.size(curLink))
+	c.setSize(curLink, newC)
 
 	if parent == nil {
 		c.root = right
@@ -568,27 +668,28 @@ func (c *embeddedMap[TKey, T]) rotateLeft(cur *T) {
 	}
 }
 
-func (c *embeddedMap[TKey, T]) rotateRight(cur *T) {
+func (c *embeddedMap[TKey, T, S]) rotateRight(cur *T) {
 	curLink := c.getLink(cur)
 	left := curLink.left
 	parent := curLink.parent
-	right := curLink.right
+
+	leftLink := c.getLink(left)
+	inner := leftLink.right
 
 	curLink.parent = left
-	curLink.left = right
+	curLink.left = inner
 
-	leftLink := c.getLink(left)
 	leftLink.parent = parent
 	leftLink.right = cur
 
-	newC := curLink.position - leftLink.position
-	if right != nil {
-		rightLink := c.getLink(right)
-		rightLink.parent = cur
-		newC += rightLink.position
+	newC := c.size(curLink) - c.size(leftLink)
+	if inner != nil {
+		innerLink := c.getLink(inner)
+		innerLink.parent = cur
+		newC += c.size(innerLink)
 	}
-	leftLink.position = curLink.position
-	curLink.position = newC
+	c.setSize(leftLink, c.size(curLink))
+	c.setSize(curLink, newC)
 
 	if parent == nil {
 		c.root = left
@@ -599,7 +700,7 @@ func (c *embeddedMap[TKey, T]) rotateRight(cur *T) {
 	}
 }
 
-func (c *embeddedMap[TKey, T]) removeFixup(cur *T) {
+func (c *embeddedMap[TKey, T, S]) removeFixup(cur *T) {
 	curLink := c.getLink(cur)
 	parent := curLink.parent
 	if parent == nil {
@@ -613,9 +714,9 @@ func (c *embeddedMap[TKey, T]) removeFixup(cur *T) {
 	}
 	if sibling != nil {
 		siblingLink := c.getLink(sibling)
-		if siblingLink.red {
-			parentLink.red = true
-			siblingLink.red = false
+		if c.isRed(siblingLink) {
+			c.setRed(parentLink, true)
+			c.setRed(siblingLink, false)
 			if parentLink.left == cur {
 				c.rotateLeft(parent)
 			} else {
@@ -634,35 +735,35 @@ func (c *embeddedMap[TKey, T]) removeFixup(cur *T) {
 	sibLeft := siblingLink.left
 	sibRight := siblingLink.right
 
-	var sibLeftLink *MapLink[TKey, T]
+	var sibLeftLink *mapNode[T]
 	if sibLeft != nil {
 		sibLeftLink = c.getLink(sibLeft)
 	}
 
-	var sibRightLink *MapLink[TKey, T]
+	var sibRightLink *mapNode[T]
 	if sibRight != nil {
 		sibRightLink = c.getLink(sibRight)
 	}
 
-	if !parentLink.red && !siblingLink.red && (sibLeftLink == nil || !sibLeftLink.red) && (sibRightLink == nil || !sibRightLink.red) {
-		siblingLink.red = true
+	if !c.isRed(parentLink) && !c.isRed(siblingLink) && (sibLeftLink == nil || !c.isRed(sibLeftLink)) && (sibRightLink == nil || !c.isRed(sibRightLink)) {
+		c.setRed(siblingLink, true)
 		c.removeFixup(parent)
 		return
 	}
 
-	if parentLink.red && !siblingLink.red && (sibLeftLink == nil || !sibLeftLink.red) && (sibRightLink == nil || !sibRightLink.red) {
-		siblingLink.red = true
-		parentLink.red = false
+	if c.isRed(parentLink) && !c.isRed(siblingLink) && (sibLeftLink == nil || !c.isRed(sibLeftLink)) && (sibRightLink == nil || !c.isRed(sibRightLink)) {
+		c.setRed(siblingLink, true)
+		c.setRed(parentLink, false)
 		return
 	}
 
-	if cur == parentLink.left && !siblingLink.red && (sibLeftLink != nil && sibLeftLink.red) && (sibRightLink == nil || !sibRightLink.red) {
-		siblingLink.red = true
-		sibLeftLink.red = false
+	if cur == parentLink.left && !c.isRed(siblingLink) && (sibLeftLink != nil && c.isRed(sibLeftLink)) && (sibRightLink == nil || !c.isRed(sibRightLink)) {
+		c.setRed(siblingLink, true)
+		c.setRed(sibLeftLink, false)
 		c.rotateRight(sibling)
-	} else if cur == parentLink.right && !siblingLink.red && (sibRightLink != nil && sibRightLink.red) && (sibLeftLink == nil || !sibLeftLink.red) {
-		siblingLink.red = true
-		sibRightLink.red = false
+	} else if cur == parentLink.right && !c.isRed(siblingLink) && (sibRightLink != nil && c.isRed(sibRightLink)) && (sibLeftLink == nil || !c.isRed(sibLeftLink)) {
+		c.setRed(siblingLink, true)
+		c.setRed(sibRightLink, false)
 		c.rotateLeft(sibling)
 	}
 
@@ -673,23 +774,23 @@ func (c *embeddedMap[TKey, T]) removeFixup(cur *T) {
 		sibling = parentLink.right
 	}
 	siblingLink = c.getLink(sibling)
-	siblingLink.red = parentLink.red
-	parentLink.red = false
+	c.setRed(siblingLink, c.isRed(parentLink))
+	c.setRed(parentLink, false)
 	if cur == parentLink.left {
 		sibRight = siblingLink.right
 		sibRightLink = c.getLink(sibRight)
-		sibRightLink.red = false
+		c.setRed(sibRightLink, false)
 		c.rotateLeft(parent)
 		return
 	}
 
 	sibLeft = siblingLink.left
 	sibLeftLink = c.getLink(sibLeft)
-	sibLeftLink.red = false
+	c.setRed(sibLeftLink, false)
 	c.rotateRight(parent)
 }
 
-func (c *embeddedMap[TKey, T]) cutNode(cur *T) {
+func (c *embeddedMap[TKey, T, S]) cutNode(cur *T) {
 	curLink := c.getLink(cur)
 	child := curLink.left
 	if curLink.left == nil {
@@ -712,7 +813,7 @@ func (c *embeddedMap[TKey, T]) cutNode(cur *T) {
 	walk := parent
 	for walk != nil {
 		walkLink := c.getLink(walk)
-		walkLink.position--
+		c.addSize(walkLink, -1)
 		walk = walkLink.parent
 	}
 }
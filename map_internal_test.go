@@ -0,0 +1,142 @@
+package embedded
+
+import (
+	"testing"
+	"unsafe"
+)
+
+type mapTreeEntry struct {
+	data int
+	link MapLink[int, mapTreeEntry]
+}
+
+func newMapTreeTest() *embeddedMap[int, mapTreeEntry, uint] {
+	return NewMap[int, mapTreeEntry](unsafe.Offsetof(mapTreeEntry{}.link)).(*embeddedMap[int, mapTreeEntry, uint])
+}
+
+func TestEmbeddedMapRotate(t *testing.T) {
+	c := newMapTreeTest()
+	for _, i := range []int{1, 0, 3, 2, 4} {
+		c.Insert(i, &mapTreeEntry{data: i})
+	}
+
+	c.rotateLeft(c.root)
+	if c.root.data != 3 || c.getLink(c.root).left.data != 1 {
+		t.Fatal("rotateLeft did not lift the right child")
+	}
+	checkMapTree(t, c)
+
+	c.rotateRight(c.root)
+	if c.root.data != 1 || c.getLink(c.root).right.data != 3 {
+		t.Fatal("rotateRight did not lift the left child")
+	}
+	checkMapTree(t, c)
+}
+
+func TestEmbeddedMapBalance(t *testing.T) {
+	const testSize = 1000
+	c := newMapTreeTest()
+	for i := 0; i < testSize; i++ {
+		c.Insert(i, &mapTreeEntry{data: i})
+	}
+	checkMapTree(t, c)
+	checkMapColors(t, c)
+
+	c = newMapTreeTest()
+	for i := 0; i < testSize; i++ {
+		c.Insert(testSize-i, &mapTreeEntry{data: i})
+	}
+	checkMapTree(t, c)
+	checkMapColors(t, c)
+}
+
+func TestEmbeddedMapRemove(t *testing.T) {
+	const testSize = 1000
+	c := newMapTreeTest()
+	entries := make([]mapTreeEntry, testSize)
+	for i := range entries {
+		entries[i].data = i
+		c.Insert(i, &entries[i])
+	}
+	for i := 0; i < testSize; i += 3 {
+		c.Remove(&entries[i])
+		checkMapTree(t, c)
+		checkMapColors(t, c)
+	}
+}
+
+// checkMapTree verifies the parent links, key order and subtree sizes of the
+// tree of c.
+func checkMapTree[TKey MapKeyType, T any, S MapCompactSizeType](t *testing.T, c *embeddedMap[TKey, T, S]) {
+	t.Helper()
+	if c.root != nil && c.getLink(c.root).parent != nil {
+		t.Fatal("map root has a parent")
+	}
+	if size := checkMapNode(t, c, c.root); size != c.count {
+		t.Fatalf("unexpected tree size (actual %d != expected %d)", size, c.count)
+	}
+	var prev *T
+	count := 0
+	for cur := c.First(); cur != nil; cur = c.Next(cur) {
+		if prev != nil && c.GetKey(cur) < c.GetKey(prev) {
+			t.Fatal("map keys out of order")
+		}
+		prev = cur
+		count++
+	}
+	if count != c.count {
+		t.Fatalf("unexpected iteration count (actual %d != expected %d)", count, c.count)
+	}
+}
+
+func checkMapNode[TKey MapKeyType, T any, S MapCompactSizeType](t *testing.T, c *embeddedMap[TKey, T, S], cur *T) int {
+	t.Helper()
+	if cur == nil {
+		return 0
+	}
+	curLink := c.getLink(cur)
+	size := 1
+	for _, child := range []*T{curLink.left, curLink.right} {
+		if child != nil && c.getLink(child).parent != cur {
+			t.Fatal("map node has a child linked to another parent")
+		}
+		size += checkMapNode(t, c, child)
+	}
+	if c.size(curLink) != size {
+		t.Fatalf("unexpected subtree size (actual %d != expected %d)", c.size(curLink), size)
+	}
+	return size
+}
+
+// checkMapColors verifies that the tree of c has a black root, that no red node
+// has a red child and that every path down to a leaf holds the same number of
+// black nodes.
+func checkMapColors[TKey MapKeyType, T any, S MapCompactSizeType](t *testing.T, c *embeddedMap[TKey, T, S]) {
+	t.Helper()
+	if c.root != nil && c.isRed(c.getLink(c.root)) {
+		t.Fatal("map root is red")
+	}
+	checkMapNodeColors(t, c, c.root)
+}
+
+func checkMapNodeColors[TKey MapKeyType, T any, S MapCompactSizeType](t *testing.T, c *embeddedMap[TKey, T, S], cur *T) int {
+	t.Helper()
+	if cur == nil {
+		return 1
+	}
+	curLink := c.getLink(cur)
+	leftHeight := checkMapNodeColors(t, c, curLink.left)
+	rightHeight := checkMapNodeColors(t, c, curLink.right)
+	if leftHeight != rightHeight {
+		t.Fatalf("unbalanced black height (left %d != right %d)", leftHeight, rightHeight)
+	}
+	if !c.isRed(curLink) {
+		return leftHeight + 1
+	}
+	for _, child := range []*T{curLink.left, curLink.right} {
+		if child != nil && c.isRed(c.getLink(child)) {
+			t.Fatal("red map node has a red child")
+		}
+	}
+	return leftHeight
+}
@@ -1,6 +1,7 @@
 package embedded_test
 
 import (
+	"math/rand"
 	"testing"
 	"unsafe"
 
@@ -15,25 +16,283 @@ type mapEntry struct {
 var mapEntryLinkField = unsafe.Offsetof(mapEntry{}.link)
 
 func TestEmbeddedMap(t *testing.T) {
+	testEmbeddedMap(t, func() embedded.Map[int, mapEntry] {
+		return embedded.NewMap[int, mapEntry](mapEntryLinkField)
+	}, func(data int) *mapEntry {
+		return &mapEntry{data: data}
+	}, func(obj *mapEntry) int {
+		return obj.data
+	})
+}
+
+// testEmbeddedMap runs the behavior every implementation of the map interface
+// must share against the maps created by newMap.
+func testEmbeddedMap[T any](t *testing.T, newMap func() embedded.Map[int, T], newEntry func(data int) *T, dataOf func(obj *T) int) {
 	const testSize = 5500
+
+	t.Run("Order", func(t *testing.T) {
+		m := newMap()
+		for _, i := range rand.New(rand.NewSource(1)).Perm(testSize) {
+			m.Insert(i, newEntry(i))
+		}
+		if actualCount := m.Count(); actualCount != testSize {
+			t.Fatalf("unexpected map count (actual %d != expected %d)", actualCount, testSize)
+		}
+
+		cur := m.Last()
+		for i := testSize - 1; i >= 0; i-- {
+			if cur == nil || dataOf(cur) != i {
+				t.Fatal("expected entry not found")
+			}
+			cur = m.Prev(cur)
+		}
+		if cur != nil {
+			t.Fatal("unexpected entry before first")
+		}
+
+		cur = m.First()
+		for i := 0; i < testSize; i++ {
+			if cur == nil || dataOf(cur) != i {
+				t.Fatal("expected entry not found")
+			}
+			if actualPosition := m.GetPosition(cur); actualPosition != i {
+				t.Fatalf("unexpected position (actual %d != expected %d)", actualPosition, i)
+			}
+			if m.Position(i) != cur {
+				t.Fatal("item not found at expected position")
+			}
+			cur = m.Next(cur)
+		}
+		if cur != nil {
+			t.Fatal("unexpected entry after last")
+		}
+		if m.Position(-1) != nil || m.Position(testSize) != nil {
+			t.Fatal("unexpected entry at out of range position")
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		m := newMap()
+		for i := 0; i < testSize; i++ {
+			m.Insert(i, newEntry(i))
+		}
+		for i := 0; i < testSize; i += 2 {
+			entry := m.Find(i)
+			if entry == nil || dataOf(entry) != i {
+				t.Fatal("expected entry not found")
+			}
+			if m.Remove(entry) != entry {
+				t.Fatal("unexpected entry removed")
+			}
+			if m.IsContained(entry) {
+				t.Fatal("embedded map reports that removed item is present")
+			}
+		}
+		if actualCount := m.Count(); actualCount != testSize/2 {
+			t.Fatalf("unexpected map count (actual %d != expected %d)", actualCount, testSize/2)
+		}
+		for i, cur := 0, m.First(); cur != nil; i, cur = i+1, m.Next(cur) {
+			if expected := i*2 + 1; dataOf(cur) != expected {
+				t.Fatalf("unexpected entry (actual %d != expected %d)", dataOf(cur), expected)
+			}
+			if !m.IsContained(cur) {
+				t.Fatal("embedded map reports that inserted item is not present")
+			}
+			if actualPosition := m.GetPosition(cur); actualPosition != i {
+				t.Fatalf("unexpected position (actual %d != expected %d)", actualPosition, i)
+			}
+		}
+
+		if first := m.RemoveFirst(); first == nil || dataOf(first) != 1 {
+			t.Fatal("unexpected first entry removed")
+		}
+		if last := m.RemoveLast(); last == nil || dataOf(last) != testSize-1 {
+			t.Fatal("unexpected last entry removed")
+		}
+		m.RemoveAll()
+		if !m.IsEmpty() || m.Count() != 0 || m.First() != nil || m.Last() != nil {
+			t.Fatal("embedded map is not empty after RemoveAll")
+		}
+		if m.RemoveFirst() != nil || m.RemoveLast() != nil {
+			t.Fatal("unexpected entry removed from empty map")
+		}
+	})
+
+	t.Run("Bounds", func(t *testing.T) {
+		m := newMap()
+		for i := 1; i < testSize; i += 2 {
+			m.Insert(i, newEntry(i))
+		}
+		if actual := m.Find(10); actual != nil {
+			t.Fatal("unexpected entry found")
+		}
+		if actual := m.FindUpperInclusive(10); actual == nil || dataOf(actual) != 11 {
+			t.Fatal("unexpected upper inclusive bound")
+		}
+		if actual := m.FindUpperInclusive(11); actual == nil || dataOf(actual) != 11 {
+			t.Fatal("unexpected upper inclusive bound")
+		}
+		if actual := m.FindLowerInclusive(10); actual == nil || dataOf(actual) != 9 {
+			t.Fatal("unexpected lower inclusive bound")
+		}
+		if actual := m.FindLowerInclusive(11); actual == nil || dataOf(actual) != 11 {
+			t.Fatal("unexpected lower inclusive bound")
+		}
+		if actual := m.FindUpperExclusive(11); actual == nil || dataOf(actual) != 13 {
+			t.Fatal("unexpected upper exclusive bound")
+		}
+		if actual := m.FindLowerExclusive(11); actual == nil || dataOf(actual) != 9 {
+			t.Fatal("unexpected lower exclusive bound")
+		}
+		if m.FindLowerInclusive(0) != nil || m.FindLowerExclusive(1) != nil {
+			t.Fatal("unexpected lower bound before first")
+		}
+		if m.FindUpperInclusive(testSize) != nil || m.FindUpperExclusive(testSize-1) != nil {
+			t.Fatal("unexpected upper bound after last")
+		}
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		m := newMap()
+		for i := 0; i < 100; i++ {
+			m.Insert(i, newEntry(i))
+		}
+		dups := []*T{m.Find(50), newEntry(50), newEntry(50)}
+		m.Insert(50, dups[1])
+		m.Insert(50, dups[2])
+
+		cur := m.FindFirst(50)
+		for _, expected := range dups {
+			if cur != expected {
+				t.Fatal("duplicate entries not found in insertion order")
+			}
+			cur = m.FindNext(cur)
+		}
+		if cur != nil {
+			t.Fatal("unexpected duplicate entry")
+		}
+		if m.FindLowerInclusive(50) != dups[0] || m.FindUpperInclusive(50) != dups[0] {
+			t.Fatal("inclusive bound is not the first duplicate entry")
+		}
+		if actual := m.FindUpperExclusive(50); actual == nil || dataOf(actual) != 51 {
+			t.Fatal("unexpected upper exclusive bound")
+		}
+		if actual := m.FindLowerExclusive(50); actual == nil || dataOf(actual) != 49 {
+			t.Fatal("unexpected lower exclusive bound")
+		}
+		if actualPosition := m.GetPosition(dups[2]); actualPosition != 52 {
+			t.Fatalf("unexpected position (actual %d != expected %d)", actualPosition, 52)
+		}
+
+		m.Remove(dups[0])
+		if m.FindFirst(50) != dups[1] || !m.IsContained(dups[2]) || m.IsContained(dups[0]) {
+			t.Fatal("unexpected duplicate entries after removal")
+		}
+	})
+
+	t.Run("Move", func(t *testing.T) {
+		m := newMap()
+		for i := 0; i < 100; i++ {
+			m.Insert(i, newEntry(i))
+		}
+		entry := m.Find(10)
+		m.Move(entry, 1000)
+		if m.Find(10) != nil || m.Find(1000) != entry || m.Last() != entry {
+			t.Fatal("moved entry not found at its new key")
+		}
+		if actualKey := m.GetKey(entry); actualKey != 1000 {
+			t.Fatalf("unexpected key (actual %d != expected %d)", actualKey, 1000)
+		}
+		if actualCount := m.Count(); actualCount != 100 {
+			t.Fatalf("unexpected map count (actual %d != expected %d)", actualCount, 100)
+		}
+	})
+}
+
+func TestEmbeddedMapDuplicateKeys(t *testing.T) {
+	const dupCount = 3
+	m := embedded.NewMap[int, mapEntry](mapEntryLinkField)
+	for i := 0; i < dupCount; i++ {
+		m.Insert(7, &mapEntry{data: i})
+	}
+
+	var found int
+	for cur := m.FindFirst(7); cur != nil; cur = m.FindNext(cur) {
+		if cur.data != found {
+			t.Fatalf("duplicate key not found in insertion order (actual %d != expected %d)", cur.data, found)
+		}
+		found++
+	}
+	if found != dupCount {
+		t.Fatalf("unexpected duplicate count (actual %d != expected %d)", found, dupCount)
+	}
+}
+
+func TestEmbeddedMapPosition(t *testing.T) {
+	const testSize = 1000
 	m := embedded.NewMap[int, mapEntry](mapEntryLinkField)
 	for i := 0; i < testSize; i++ {
 		m.Insert(i, &mapEntry{data: i})
 	}
 
-	cur := m.Last()
-	for i := testSize - 1; i >= 0; i-- {
-		if cur == nil || cur.data != i {
-			t.Fatal("expected entry not found")
+	cur := m.First()
+	for i := 0; i < testSize; i++ {
+		if actualPosition := m.GetPosition(cur); actualPosition != i {
+			t.Fatalf("unexpected position (actual %d != expected %d)", actualPosition, i)
+		}
+		if m.Position(i) != cur {
+			t.Fatalf("item not found at position %d", i)
 		}
-		cur = m.Prev(cur)
+		cur = m.Next(cur)
+	}
+	if m.Position(-1) != nil || m.Position(testSize) != nil {
+		t.Fatal("unexpected entry at out of range position")
 	}
 }
 
 func BenchmarkEmbeddedMap_Insert(b *testing.B) {
 	m := embedded.NewMap[int, mapEntry](mapEntryLinkField)
 	b.ReportAllocs()
+	b.ReportMetric(float64(unsafe.Sizeof(mapEntry{}.link)), "link-bytes")
+	for i := 0; i < b.N; i++ {
+		m.Insert(i, &mapEntry{data: i})
+	}
+}
+
+func BenchmarkEmbeddedMap_Find(b *testing.B) {
+	const mapSize = 1 << 16
+	m := embedded.NewMap[int, mapEntry](mapEntryLinkField)
+	for i := 0; i < mapSize; i++ {
+		m.Insert(i, &mapEntry{data: i})
+	}
+	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
+		m.Find(i % mapSize)
+	}
+}
+
+func BenchmarkEmbeddedMap_Scan(b *testing.B) {
+	const mapSize = 1 << 16
+	m := embedded.NewMap[int, mapEntry](mapEntryLinkField)
+	for i := 0; i < mapSize; i++ {
 		m.Insert(i, &mapEntry{data: i})
 	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for cur := m.First(); cur != nil; cur = m.Next(cur) {
+		}
+	}
+}
+
+func BenchmarkEmbeddedMap_FindZipf(b *testing.B) {
+	const mapSize = 1 << 16
+	m := embedded.NewMap[int, mapEntry](mapEntryLinkField)
+	for _, i := range rand.New(rand.NewSource(1)).Perm(mapSize) {
+		m.Insert(i, &mapEntry{data: i})
+	}
+	keys := zipfKeys(mapSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Find(keys[i%len(keys)])
+	}
 }
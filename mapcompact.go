@@ -0,0 +1,32 @@
+package embedded
+
+import (
+	"math"
+	"unsafe"
+)
+
+// This is a map container with the same mechanisms as embedded.Map, but with
+// a smaller link - the red-black color is packed into the low bit of the
+// subtree size. Choosing uint32 for S shrinks the link further, at the cost of
+// limiting the container to MaxInt32 items.
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+type MapCompactSizeType interface {
+	~uint | ~uint32 | ~uint64
+}
+
+func NewMapCompact[TKey MapKeyType, T any, S MapCompactSizeType](linkField uintptr) Map[TKey, T] {
+	var mcl MapCompactLink[TKey, T, S]
+	maxCount := 0
+	if limit := uint64(^S(0) >> 1); limit < math.MaxInt {
+		maxCount = int(limit)
+	}
+	return &embeddedMap[TKey, T, S]{
+		nodeField: linkField + unsafe.Offsetof(mcl.node),
+		metaField: linkField + unsafe.Offsetof(mcl.meta),
+		keyField:  linkField + unsafe.Offsetof(mcl.key),
+		packed:    true,
+		maxCount:  maxCount,
+	}
+}
@@ -0,0 +1,91 @@
+package embedded_test
+
+import (
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type mapCompactEntry struct {
+	data int
+	link embedded.MapCompactLink[int, mapCompactEntry, uint]
+}
+
+var mapCompactEntryLinkField = unsafe.Offsetof(mapCompactEntry{}.link)
+
+type mapCompact32Entry struct {
+	data int32
+	link embedded.MapCompactLink[int32, mapCompact32Entry, uint32]
+}
+
+var mapCompact32EntryLinkField = unsafe.Offsetof(mapCompact32Entry{}.link)
+
+type mapCompactSmallEntry struct {
+	data int
+	link embedded.MapCompactLink[int, mapCompactSmallEntry, uint32]
+}
+
+var mapCompactSmallEntryLinkField = unsafe.Offsetof(mapCompactSmallEntry{}.link)
+
+func TestEmbeddedMapCompact(t *testing.T) {
+	testEmbeddedMap(t, func() embedded.Map[int, mapCompactEntry] {
+		return embedded.NewMapCompact[int, mapCompactEntry, uint](mapCompactEntryLinkField)
+	}, func(data int) *mapCompactEntry {
+		return &mapCompactEntry{data: data}
+	}, func(obj *mapCompactEntry) int {
+		return obj.data
+	})
+}
+
+func TestEmbeddedMapCompact32(t *testing.T) {
+	testEmbeddedMap(t, func() embedded.Map[int, mapCompactSmallEntry] {
+		return embedded.NewMapCompact[int, mapCompactSmallEntry, uint32](mapCompactSmallEntryLinkField)
+	}, func(data int) *mapCompactSmallEntry {
+		return &mapCompactSmallEntry{data: data}
+	}, func(obj *mapCompactSmallEntry) int {
+		return obj.data
+	})
+}
+
+func TestEmbeddedMapCompactLinkSize(t *testing.T) {
+	if linkSize, mapLinkSize := unsafe.Sizeof(mapCompactEntry{}.link), unsafe.Sizeof(mapEntry{}.link); linkSize >= mapLinkSize {
+		t.Fatalf("compact link is not smaller than the map link (compact %d >= map %d)", linkSize, mapLinkSize)
+	}
+}
+
+func TestEmbeddedMapCompact32LinkSize(t *testing.T) {
+	if linkSize, expectedSize := unsafe.Sizeof(mapCompact32Entry{}.link), 4*unsafe.Sizeof(uintptr(0)); linkSize > expectedSize {
+		t.Fatalf("compact link is larger than expected (actual %d > expected %d)", linkSize, expectedSize)
+	}
+}
+
+func BenchmarkEmbeddedMapCompact_Insert(b *testing.B) {
+	m := embedded.NewMapCompact[int, mapCompactEntry, uint](mapCompactEntryLinkField)
+	b.ReportAllocs()
+	b.ReportMetric(float64(unsafe.Sizeof(mapCompactEntry{}.link)), "link-bytes")
+	for i := 0; i < b.N; i++ {
+		m.Insert(i, &mapCompactEntry{data: i})
+	}
+}
+
+func BenchmarkEmbeddedMapCompact32_Insert(b *testing.B) {
+	m := embedded.NewMapCompact[int32, mapCompact32Entry, uint32](mapCompact32EntryLinkField)
+	b.ReportAllocs()
+	b.ReportMetric(float64(unsafe.Sizeof(mapCompact32Entry{}.link)), "link-bytes")
+	for i := 0; i < b.N; i++ {
+		m.Insert(int32(i), &mapCompact32Entry{data: int32(i)})
+	}
+}
+
+func BenchmarkEmbeddedMapCompact_Find(b *testing.B) {
+	const mapSize = 1 << 16
+	m := embedded.NewMapCompact[int, mapCompactEntry, uint](mapCompactEntryLinkField)
+	for i := 0; i < mapSize; i++ {
+		m.Insert(i, &mapCompactEntry{data: i})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Find(i % mapSize)
+	}
+}
@@ -0,0 +1,8 @@
+package embedded
+
+// MapCompactLink is a link to the compact map container
+type MapCompactLink[TKey, T any, S MapCompactSizeType] struct {
+	node mapNode[T]
+	meta S
+	key  TKey
+}
@@ -0,0 +1,155 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// This is a map container ordered by a key which is read from the object
+// itself - it allows for ordered iteration over its contents without storing
+// a copy of the key in the link.
+// The key extractor must return the same value for an object for as long as
+// it is contained; call Move after mutating the key to re-sort the object.
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+type MapKeyFunc[TKey MapKeyType, T any] interface {
+	First() *T
+	Last() *T
+	Next(cur *T) *T
+	Prev(cur *T) *T
+	Position(index int) *T
+	Count() int
+
+	Remove(obj *T) *T
+	RemoveFirst() *T
+	RemoveLast() *T
+	RemoveAll()
+
+	Insert(obj *T) *T
+
+	Move(obj *T)
+
+	GetKey(obj *T) TKey
+	IsEmpty() bool
+
+	IsContained(obj *T) bool
+
+	GetPosition(obj *T) int
+
+	Find(key TKey) *T
+	FindFirst(key TKey) *T
+	FindNext(cur *T) *T
+	FindLowerInclusive(key TKey) *T
+	FindUpperInclusive(key TKey) *T
+	FindLowerExclusive(key TKey) *T
+	FindUpperExclusive(key TKey) *T
+}
+
+func NewMapKeyFunc[TKey MapKeyType, T any](linkField uintptr, keyOf func(obj *T) TKey) MapKeyFunc[TKey, T] {
+	var mkl MapKeyFuncLink[T]
+	return &embeddedMapKeyFunc[TKey, T]{
+		tree: &embeddedMap[TKey, T, uint]{
+			nodeField: linkField + unsafe.Offsetof(mkl.node),
+			metaField: linkField + unsafe.Offsetof(mkl.position),
+			redField:  linkField + unsafe.Offsetof(mkl.red),
+			keyOf:     keyOf,
+		},
+	}
+}
+
+type embeddedMapKeyFunc[TKey MapKeyType, T any] struct {
+	tree *embeddedMap[TKey, T, uint]
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) First() *T {
+	return c.tree.First()
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) Last() *T {
+	return c.tree.Last()
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) Next(cur *T) *T {
+	return c.tree.Next(cur)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) Prev(cur *T) *T {
+	return c.tree.Prev(cur)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) Position(index int) *T {
+	return c.tree.Position(index)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) Count() int {
+	return c.tree.Count()
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) Remove(obj *T) *T {
+	return c.tree.Remove(obj)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) RemoveFirst() *T {
+	return c.tree.RemoveFirst()
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) RemoveLast() *T {
+	return c.tree.RemoveLast()
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) RemoveAll() {
+	c.tree.RemoveAll()
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) Insert(obj *T) *T {
+	return c.tree.insert(obj)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) Move(obj *T) {
+	c.tree.Remove(obj)
+	c.tree.insert(obj)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) GetKey(obj *T) TKey {
+	return c.tree.GetKey(obj)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) IsEmpty() bool {
+	return c.tree.IsEmpty()
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) IsContained(obj *T) bool {
+	return c.tree.IsContained(obj)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) GetPosition(obj *T) int {
+	return c.tree.GetPosition(obj)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) Find(key TKey) *T {
+	return c.tree.Find(key)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) FindFirst(key TKey) *T {
+	return c.tree.FindFirst(key)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) FindNext(cur *T) *T {
+	return c.tree.FindNext(cur)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) FindLowerInclusive(key TKey) *T {
+	return c.tree.FindLowerInclusive(key)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) FindUpperInclusive(key TKey) *T {
+	return c.tree.FindUpperInclusive(key)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) FindLowerExclusive(key TKey) *T {
+	return c.tree.FindLowerExclusive(key)
+}
+
+func (c *embeddedMapKeyFunc[TKey, T]) FindUpperExclusive(key TKey) *T {
+	return c.tree.FindUpperExclusive(key)
+}
@@ -0,0 +1,83 @@
+package embedded_test
+
+import (
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type mapKeyFuncEntry struct {
+	data int
+	link embedded.MapKeyFuncLink[mapKeyFuncEntry]
+}
+
+var mapKeyFuncEntryLinkField = unsafe.Offsetof(mapKeyFuncEntry{}.link)
+
+func mapKeyFuncEntryKey(obj *mapKeyFuncEntry) int {
+	return obj.data
+}
+
+func TestEmbeddedMapKeyFunc(t *testing.T) {
+	const testSize = 5500
+	m := embedded.NewMapKeyFunc(mapKeyFuncEntryLinkField, mapKeyFuncEntryKey)
+	for i := 0; i < testSize; i++ {
+		m.Insert(&mapKeyFuncEntry{data: i})
+	}
+
+	cur := m.Last()
+	for i := testSize - 1; i >= 0; i-- {
+		if cur == nil || cur.data != i {
+			t.Fatal("expected entry not found")
+		}
+		if actualKey := m.GetKey(cur); actualKey != i {
+			t.Fatalf("key mismatch detected (actual %d != expected %d)", actualKey, i)
+		}
+		if actualPosition := m.GetPosition(cur); actualPosition != i {
+			t.Fatalf("unexpected position (actual %d != expected %d)", actualPosition, i)
+		}
+		if m.Position(i) != cur {
+			t.Fatal("item not found at expected position")
+		}
+		cur = m.Prev(cur)
+	}
+
+	moveItem := m.First()
+	moveItem.data = testSize
+	m.Move(moveItem)
+	if m.Last() != moveItem {
+		t.Fatal("moved item was not re-sorted to the end of the map")
+	}
+	if m.Find(testSize) != moveItem {
+		t.Fatal("moved item could not be found by its new key")
+	}
+	if m.Find(0) != nil {
+		t.Fatal("moved item still found by its old key")
+	}
+
+	dup := &mapKeyFuncEntry{data: testSize}
+	m.Insert(dup)
+	if m.FindFirst(testSize) != moveItem || m.FindNext(moveItem) != dup {
+		t.Fatal("duplicate key not found in insertion order")
+	}
+
+	if actualCount := m.Count(); actualCount != testSize+1 {
+		t.Fatalf("unexpected map count (actual %d != expected %d)", actualCount, testSize+1)
+	}
+
+	m.Remove(dup)
+	if m.IsContained(dup) {
+		t.Fatal("embedded map reports that removed item is present")
+	}
+	if !m.IsContained(moveItem) {
+		t.Fatal("embedded map reports that contained item is not present")
+	}
+}
+
+func BenchmarkEmbeddedMapKeyFunc_Insert(b *testing.B) {
+	m := embedded.NewMapKeyFunc(mapKeyFuncEntryLinkField, mapKeyFuncEntryKey)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.Insert(&mapKeyFuncEntry{data: i})
+	}
+}
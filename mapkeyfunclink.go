@@ -0,0 +1,8 @@
+package embedded
+
+// MapKeyFuncLink is a link to the key extractor map container
+type MapKeyFuncLink[T any] struct {
+	node     mapNode[T]
+	red      bool
+	position uint
+}
@@ -4,17 +4,27 @@ import (
 	"unsafe"
 )
 
+// mapRedBit is the bit of the subtree size of a compact map node holding its
+// color.
+const mapRedBit = 1
+
 // MapLink is a link to the map container
 type MapLink[TKey, T any] struct {
 	key      TKey
-	parent   *T
-	left     *T
-	right    *T
+	node     mapNode[T]
 	red      bool
-	position int
+	position uint
+}
+
+// mapNode places an item in the red-black tree of a map container. The color
+// and subtree size of the node are kept next to it in the link.
+type mapNode[T any] struct {
+	parent *T
+	left   *T
+	right  *T
 }
 
-func getMapLink[TKey, T any](obj *T, linkFieldOfs uintptr) *MapLink[TKey, T] {
-	u := unsafe.Add(unsafe.Pointer(obj), linkFieldOfs)
-	return (*MapLink[TKey, T])(u)
+func getMapNode[T any](obj *T, nodeFieldOfs uintptr) *mapNode[T] {
+	u := unsafe.Add(unsafe.Pointer(obj), nodeFieldOfs)
+	return (*mapNode[T])(u)
 }
@@ -0,0 +1,59 @@
+package embedded
+
+// OverflowPolicy selects what a bounded container does when an item is
+// inserted while it is at capacity.
+type OverflowPolicy int
+
+const (
+	// OverflowReject drops the incoming item, leaving the container as is.
+	OverflowReject = OverflowPolicy(iota)
+	// OverflowEvictFirst removes the first item to make room.
+	OverflowEvictFirst
+	// OverflowEvictLast removes the last item to make room.
+	OverflowEvictLast
+	// OverflowCallback asks the container's overflow callback which item to
+	// remove; the callback returns an item of the container, or nil to drop
+	// the incoming item. The container panics if its IsContained reports that
+	// it does not hold the returned item; for a bounded list this only catches
+	// an item in no list, not an item of another list.
+	OverflowCallback
+)
+
+type overflowHandler[T any] struct {
+	capacity   int
+	policy     OverflowPolicy
+	onOverflow func(incoming *T) *T
+}
+
+func newOverflowHandler[T any](capacity int, policy OverflowPolicy, onOverflow func(incoming *T) *T) overflowHandler[T] {
+	if capacity <= 0 {
+		panic("bounded container capacity must be positive")
+	}
+	if policy == OverflowCallback && onOverflow == nil {
+		panic("overflow callback policy requires a callback")
+	}
+	return overflowHandler[T]{
+		capacity:   capacity,
+		policy:     policy,
+		onOverflow: onOverflow,
+	}
+}
+
+// victim returns the item to drop so that incoming fits, which is incoming
+// itself when it is to be rejected, or nil when there is room for it.
+func (h *overflowHandler[T]) victim(count int, first, last, incoming *T) *T {
+	if count < h.capacity {
+		return nil
+	}
+	switch h.policy {
+	case OverflowEvictFirst:
+		return first
+	case OverflowEvictLast:
+		return last
+	case OverflowCallback:
+		if victim := h.onOverflow(incoming); victim != nil {
+			return victim
+		}
+	}
+	return incoming
+}
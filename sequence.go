@@ -0,0 +1,381 @@
+package embedded
+
+// This is a sequence container - it keeps its contents in an order chosen by
+// the application and, unlike List, can find, insert or remove the item at
+// any position in O(log n). Internally, it is a treap keyed by position, so
+// whole sequences can also be split apart and joined together in O(log n).
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+type Sequence[T any] interface {
+	First() *T
+	Last() *T
+	Next(cur *T) *T
+	Prev(cur *T) *T
+	At(index int) *T
+	IndexOf(obj *T) int
+	Count() int
+
+	Remove(obj *T) *T
+	RemoveAt(index int) *T
+	RemoveFirst() *T
+	RemoveLast() *T
+	RemoveAll()
+
+	InsertAt(index int, obj *T) *T
+	InsertFirst(obj *T) *T
+	InsertLast(obj *T) *T
+	InsertAfter(prev, obj *T) *T
+	InsertBefore(after, obj *T) *T
+
+	Split(index int) Sequence[T]
+	Concat(other Sequence[T])
+
+	IsEmpty() bool
+	IsContained(obj *T) bool
+}
+
+const sequenceSeed = 0x9E3779B97F4A7C15
+
+func NewSequence[T any](linkField uintptr) Sequence[T] {
+	return &embeddedSequence[T]{
+		linkField: linkField,
+		seed:      sequenceSeed,
+	}
+}
+
+type embeddedSequence[T any] struct {
+	root      *T
+	linkField uintptr
+	seed      uint64
+}
+
+func (c *embeddedSequence[T]) getLink(obj *T) *SequenceLink[T] {
+	return getSequenceLink(obj, c.linkField)
+}
+
+func (c *embeddedSequence[T]) size(obj *T) int {
+	if obj == nil {
+		return 0
+	}
+	return c.getLink(obj).size
+}
+
+func (c *embeddedSequence[T]) randomPriority() uint32 {
+	// xorshift64*
+	c.seed ^= c.seed >> 12
+	c.seed ^= c.seed << 25
+	c.seed ^= c.seed >> 27
+	return uint32((c.seed * 0x2545F4914F6CDD1D) >> 32)
+}
+
+func (c *embeddedSequence[T]) First() *T {
+	var prev *T
+	cur := c.root
+	for cur != nil {
+		prev = cur
+		cur = c.getLink(cur).left
+	}
+	return prev
+}
+
+func (c *embeddedSequence[T]) Last() *T {
+	var prev *T
+	cur := c.root
+	for cur != nil {
+		prev = cur
+		cur = c.getLink(cur).right
+	}
+	return prev
+}
+
+func (c *embeddedSequence[T]) Next(cur *T) *T {
+	curLink := c.getLink(cur)
+	if curLink.right != nil {
+		walk := curLink.right
+		for {
+			walkLink := c.getLink(walk)
+			if walkLink.left == nil {
+				break
+			}
+			walk = walkLink.left
+		}
+		return walk
+	}
+
+	curParent := curLink.parent
+	for curParent != nil && c.getLink(curParent).right == cur {
+		cur = curParent
+		curParent = c.getLink(cur).parent
+	}
+	return curParent
+}
+
+func (c *embeddedSequence[T]) Prev(cur *T) *T {
+	curLink := c.getLink(cur)
+	if curLink.left != nil {
+		walk := curLink.left
+		for {
+			walkLink := c.getLink(walk)
+			if walkLink.right == nil {
+				break
+			}
+			walk = walkLink.right
+		}
+		return walk
+	}
+
+	curParent := curLink.parent
+	for curParent != nil && c.getLink(curParent).left == cur {
+		cur = curParent
+		curParent = c.getLink(cur).parent
+	}
+	return curParent
+}
+
+func (c *embeddedSequence[T]) At(index int) *T {
+	walk := c.root
+	for walk != nil {
+		walkLink := c.getLink(walk)
+		leftSize := c.size(walkLink.left)
+		if index < leftSize {
+			walk = walkLink.left
+		} else if leftSize < index {
+			index -= leftSize + 1
+			walk = walkLink.right
+		} else {
+			return walk
+		}
+	}
+	return nil
+}
+
+func (c *embeddedSequence[T]) IndexOf(obj *T) int {
+	position := c.size(c.getLink(obj).left)
+	walk := obj
+	for {
+		parent := c.getLink(walk).parent
+		if parent == nil {
+			break
+		}
+		if parentLink := c.getLink(parent); parentLink.right == walk {
+			position += c.size(parentLink.left) + 1
+		}
+		walk = parent
+	}
+	return position
+}
+
+func (c *embeddedSequence[T]) Count() int {
+	return c.size(c.root)
+}
+
+func (c *embeddedSequence[T]) Remove(obj *T) *T {
+	objLink := c.getLink(obj)
+	if objLink.parent == nil && c.root != obj {
+		return nil
+	}
+
+	parent := objLink.parent
+	child := c.merge(objLink.left, objLink.right)
+	c.replaceChild(parent, obj, child)
+	if child != nil {
+		c.getLink(child).parent = parent
+	}
+	for walk := parent; walk != nil; walk = c.getLink(walk).parent {
+		c.getLink(walk).size--
+	}
+
+	objLink.parent = nil
+	objLink.left = nil
+	objLink.right = nil
+	objLink.size = 0
+	return obj
+}
+
+func (c *embeddedSequence[T]) RemoveAt(index int) *T {
+	obj := c.At(index)
+	if obj == nil {
+		return nil
+	}
+	return c.Remove(obj)
+}
+
+func (c *embeddedSequence[T]) RemoveFirst() *T {
+	head := c.First()
+	if head == nil {
+		return nil
+	}
+	return c.Remove(head)
+}
+
+func (c *embeddedSequence[T]) RemoveLast() *T {
+	tail := c.Last()
+	if tail == nil {
+		return nil
+	}
+	return c.Remove(tail)
+}
+
+func (c *embeddedSequence[T]) RemoveAll() {
+	c.root = nil
+}
+
+func (c *embeddedSequence[T]) InsertAt(index int, obj *T) *T {
+	if index < 0 || index > c.Count() {
+		panic("sequence index out of range")
+	}
+
+	objLink := c.getLink(obj)
+	objLink.parent = nil
+	objLink.left = nil
+	objLink.right = nil
+	objLink.priority = c.randomPriority()
+	objLink.size = 1
+
+	left, right := c.split(c.root, index)
+	c.setRoot(c.merge(c.merge(left, obj), right))
+	return obj
+}
+
+func (c *embeddedSequence[T]) InsertFirst(obj *T) *T {
+	return c.InsertAt(0, obj)
+}
+
+func (c *embeddedSequence[T]) InsertLast(obj *T) *T {
+	return c.InsertAt(c.Count(), obj)
+}
+
+func (c *embeddedSequence[T]) InsertAfter(prev, obj *T) *T {
+	if prev == nil {
+		return c.InsertFirst(obj)
+	}
+	return c.InsertAt(c.IndexOf(prev)+1, obj)
+}
+
+func (c *embeddedSequence[T]) InsertBefore(after, obj *T) *T {
+	if after == nil {
+		return c.InsertLast(obj)
+	}
+	return c.InsertAt(c.IndexOf(after), obj)
+}
+
+// Split moves the items from index onward into a new sequence, which is
+// returned.
+func (c *embeddedSequence[T]) Split(index int) Sequence[T] {
+	if index < 0 || index > c.Count() {
+		panic("sequence index out of range")
+	}
+
+	left, right := c.split(c.root, index)
+	c.setRoot(left)
+	other := &embeddedSequence[T]{
+		linkField: c.linkField,
+		seed:      c.seed*0x2545F4914F6CDD1D | 1,
+	}
+	other.setRoot(right)
+	return other
+}
+
+// Concat moves all the items of other to the end of this sequence,
+// leaving other empty.
+func (c *embeddedSequence[T]) Concat(other Sequence[T]) {
+	o, ok := other.(*embeddedSequence[T])
+	if !ok || o.linkField != c.linkField {
+		panic("cannot concatenate sequences using different links")
+	}
+	if o == c {
+		panic("cannot concatenate a sequence to itself")
+	}
+
+	c.setRoot(c.merge(c.root, o.root))
+	o.root = nil
+}
+
+func (c *embeddedSequence[T]) IsEmpty() bool {
+	return c.root == nil
+}
+
+func (c *embeddedSequence[T]) IsContained(obj *T) bool {
+	walk := obj
+	for {
+		parent := c.getLink(walk).parent
+		if parent == nil {
+			return walk == c.root
+		}
+		walk = parent
+	}
+}
+
+func (c *embeddedSequence[T]) setRoot(root *T) {
+	c.root = root
+	if root != nil {
+		c.getLink(root).parent = nil
+	}
+}
+
+func (c *embeddedSequence[T]) replaceChild(parent *T, oldChild *T, newChild *T) {
+	if parent == nil {
+		c.root = newChild
+	} else if parentLink := c.getLink(parent); parentLink.left == oldChild {
+		parentLink.left = newChild
+	} else {
+		parentLink.right = newChild
+	}
+}
+
+// split separates the first count items of the subtree at cur from the rest,
+// returning the roots of both parts. The parents of the returned roots are
+// left for the caller to set.
+func (c *embeddedSequence[T]) split(cur *T, count int) (*T, *T) {
+	if cur == nil {
+		return nil, nil
+	}
+
+	curLink := c.getLink(cur)
+	if leftSize := c.size(curLink.left); leftSize < count {
+		left, right := c.split(curLink.right, count-leftSize-1)
+		curLink.right = left
+		if left != nil {
+			c.getLink(left).parent = cur
+		}
+		curLink.size = leftSize + c.size(left) + 1
+		return cur, right
+	}
+
+	left, right := c.split(curLink.left, count)
+	curLink.left = right
+	if right != nil {
+		c.getLink(right).parent = cur
+	}
+	curLink.size = c.size(right) + c.size(curLink.right) + 1
+	return left, cur
+}
+
+// merge joins the subtrees at left and right, with every item of left ordered
+// before those of right, returning the root of the result. The parent of the
+// returned root is left for the caller to set.
+func (c *embeddedSequence[T]) merge(left, right *T) *T {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	leftLink := c.getLink(left)
+	rightLink := c.getLink(right)
+	if leftLink.priority > rightLink.priority {
+		child := c.merge(leftLink.right, right)
+		leftLink.right = child
+		c.getLink(child).parent = left
+		leftLink.size = c.size(leftLink.left) + c.size(child) + 1
+		return left
+	}
+
+	child := c.merge(left, rightLink.left)
+	rightLink.left = child
+	c.getLink(child).parent = right
+	rightLink.size = c.size(child) + c.size(rightLink.right) + 1
+	return right
+}
@@ -0,0 +1,158 @@
+package embedded_test
+
+import (
+	"math/rand"
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type sequenceEntry struct {
+	data int
+	link embedded.SequenceLink[sequenceEntry]
+}
+
+var sequenceEntryLinkField = unsafe.Offsetof(sequenceEntry{}.link)
+
+func TestEmbeddedSequence(t *testing.T) {
+	const testSize = 5500
+	s := embedded.NewSequence[sequenceEntry](sequenceEntryLinkField)
+	var expected []*sequenceEntry
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < testSize; i++ {
+		index := r.Intn(len(expected) + 1)
+		entry := s.InsertAt(index, &sequenceEntry{data: i})
+		expected = append(expected[:index], append([]*sequenceEntry{entry}, expected[index:]...)...)
+	}
+	testEmbeddedSequenceOrder(t, s, expected)
+
+	for i := 0; i < testSize/2; i++ {
+		index := r.Intn(len(expected))
+		entry := expected[index]
+		if i%2 == 0 {
+			if s.RemoveAt(index) != entry {
+				t.Fatal("unexpected entry removed")
+			}
+		} else if s.Remove(entry) != entry {
+			t.Fatal("unexpected entry removed")
+		}
+		if s.IsContained(entry) {
+			t.Fatal("embedded sequence reports that removed item is present")
+		}
+		expected = append(expected[:index], expected[index+1:]...)
+	}
+	testEmbeddedSequenceOrder(t, s, expected)
+
+	if s.At(-1) != nil || s.At(len(expected)) != nil || s.RemoveAt(len(expected)) != nil {
+		t.Fatal("unexpected entry at out of range index")
+	}
+
+	first := expected[0]
+	s.InsertAfter(first, &sequenceEntry{data: -1})
+	if actual := s.At(1); actual == nil || actual.data != -1 {
+		t.Fatal("entry not inserted after expected entry")
+	}
+	s.InsertBefore(first, &sequenceEntry{data: -2})
+	if actual := s.First(); actual == nil || actual.data != -2 {
+		t.Fatal("entry not inserted before expected entry")
+	}
+	s.RemoveFirst()
+	s.Remove(s.At(1))
+	testEmbeddedSequenceOrder(t, s, expected)
+
+	s.RemoveAll()
+	if !s.IsEmpty() || s.Count() != 0 || s.First() != nil || s.Last() != nil {
+		t.Fatal("embedded sequence is not empty after RemoveAll")
+	}
+}
+
+func TestEmbeddedSequenceSplitConcat(t *testing.T) {
+	const testSize = 1000
+	s := embedded.NewSequence[sequenceEntry](sequenceEntryLinkField)
+	var expected []*sequenceEntry
+	for i := 0; i < testSize; i++ {
+		expected = append(expected, s.InsertLast(&sequenceEntry{data: i}))
+	}
+
+	tail := s.Split(testSize / 3)
+	testEmbeddedSequenceOrder(t, s, expected[:testSize/3])
+	testEmbeddedSequenceOrder(t, tail, expected[testSize/3:])
+	if s.IsContained(expected[testSize/3]) || !tail.IsContained(expected[testSize/3]) {
+		t.Fatal("split item reported in the wrong sequence")
+	}
+
+	tail.InsertLast(s.RemoveFirst())
+	expected = append(expected[1:], expected[0])
+	s.Concat(tail)
+	testEmbeddedSequenceOrder(t, s, expected)
+	if !tail.IsEmpty() {
+		t.Fatal("concatenated sequence is not empty")
+	}
+
+	empty := s.Split(s.Count())
+	if !empty.IsEmpty() {
+		t.Fatal("split at end is not empty")
+	}
+	empty.InsertLast(&sequenceEntry{data: testSize})
+	s.Concat(empty)
+	if actual := s.Last(); actual == nil || actual.data != testSize {
+		t.Fatal("entry not found after concatenation")
+	}
+}
+
+func testEmbeddedSequenceOrder(t *testing.T, s embedded.Sequence[sequenceEntry], expected []*sequenceEntry) {
+	t.Helper()
+	if actualCount := s.Count(); actualCount != len(expected) {
+		t.Fatalf("unexpected sequence count (actual %d != expected %d)", actualCount, len(expected))
+	}
+	cur := s.First()
+	for i, entry := range expected {
+		if cur != entry {
+			t.Fatalf("unexpected entry at index %d", i)
+		}
+		if s.At(i) != entry {
+			t.Fatalf("entry not found at index %d", i)
+		}
+		if actualIndex := s.IndexOf(entry); actualIndex != i {
+			t.Fatalf("unexpected index (actual %d != expected %d)", actualIndex, i)
+		}
+		if !s.IsContained(entry) {
+			t.Fatal("embedded sequence reports that inserted item is not present")
+		}
+		cur = s.Next(cur)
+	}
+	if cur != nil {
+		t.Fatal("unexpected entry after last")
+	}
+
+	cur = s.Last()
+	for i := len(expected) - 1; i >= 0; i-- {
+		if cur != expected[i] {
+			t.Fatalf("unexpected entry at index %d", i)
+		}
+		cur = s.Prev(cur)
+	}
+}
+
+func BenchmarkEmbeddedSequence_InsertAt(b *testing.B) {
+	s := embedded.NewSequence[sequenceEntry](sequenceEntryLinkField)
+	r := rand.New(rand.NewSource(1))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.InsertAt(r.Intn(i+1), &sequenceEntry{data: i})
+	}
+}
+
+func BenchmarkEmbeddedSequence_At(b *testing.B) {
+	const sequenceSize = 1 << 16
+	s := embedded.NewSequence[sequenceEntry](sequenceEntryLinkField)
+	for i := 0; i < sequenceSize; i++ {
+		s.InsertLast(&sequenceEntry{data: i})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.At(i % sequenceSize)
+	}
+}
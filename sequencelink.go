@@ -0,0 +1,19 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// SequenceLink is a link to the sequence container
+type SequenceLink[T any] struct {
+	parent   *T
+	left     *T
+	right    *T
+	priority uint32
+	size     int
+}
+
+func getSequenceLink[T any](obj *T, linkFieldOfs uintptr) *SequenceLink[T] {
+	u := unsafe.Add(unsafe.Pointer(obj), linkFieldOfs)
+	return (*SequenceLink[T])(u)
+}
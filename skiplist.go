@@ -0,0 +1,370 @@
+package embedded
+
+import (
+	"sync/atomic"
+)
+
+// This is a skip list container - it allows for ordered iteration over its
+// contents as well as fast lookups by key.
+// Any number of goroutines may read from it (Find*, First, Last, Next, Prev,
+// GetKey, Count, IsEmpty and IsContained) while a single goroutine modifies
+// it; links are published with atomic stores, so readers never observe a
+// partially inserted item. Every insert, including the one done by Move,
+// allocates a new tower holding the key and forward links of the item and
+// publishes it, so readers positioned on an item while it is moved continue
+// from its new place. Remove replaces the tower with one holding only the key,
+// which costs another allocation but keeps a removed item from holding its old
+// links, and the items they point to, alive. Lookups which reach a removed item
+// start over; Next returns nil for it.
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+type SkipList[TKey MapKeyType, T any] interface {
+	First() *T
+	Last() *T
+	Next(cur *T) *T
+	Prev(cur *T) *T
+	Count() int
+
+	Remove(obj *T) *T
+	RemoveFirst() *T
+	RemoveLast() *T
+	RemoveAll()
+
+	Insert(key TKey, obj *T) *T
+
+	Move(obj *T, newKey TKey)
+
+	GetKey(obj *T) TKey
+	IsEmpty() bool
+
+	IsContained(obj *T) bool
+
+	Find(key TKey) *T
+	FindFirst(key TKey) *T
+	FindNext(cur *T) *T
+	FindLowerInclusive(key TKey) *T
+	FindUpperInclusive(key TKey) *T
+	FindLowerExclusive(key TKey) *T
+	FindUpperExclusive(key TKey) *T
+}
+
+const (
+	skipListMaxLevel = 32
+	skipListSeed     = 0x9E3779B97F4A7C15
+)
+
+func NewSkipList[TKey MapKeyType, T any](linkField uintptr) SkipList[TKey, T] {
+	c := &embeddedSkipList[TKey, T]{
+		linkField: linkField,
+		seed:      skipListSeed,
+	}
+	c.level.Store(1)
+	return c
+}
+
+type embeddedSkipList[TKey MapKeyType, T any] struct {
+	head      [skipListMaxLevel]atomic.Pointer[T]
+	tail      atomic.Pointer[T]
+	level     atomic.Int32
+	count     atomic.Int64
+	linkField uintptr
+	seed      uint64
+}
+
+func (c *embeddedSkipList[TKey, T]) getLink(obj *T) *SkipListLink[TKey, T] {
+	return getSkipListLink[TKey](obj, c.linkField)
+}
+
+func (c *embeddedSkipList[TKey, T]) getTower(obj *T) *skipListTower[TKey, T] {
+	return c.getLink(obj).tower.Load()
+}
+
+func (c *embeddedSkipList[TKey, T]) nextOf(obj *T, level int) *atomic.Pointer[T] {
+	if obj == nil {
+		return &c.head[level]
+	}
+	return &c.getTower(obj).next[level]
+}
+
+// loadNext returns the item after obj at level, or nil when obj has no link at
+// that level, such as once it has been removed.
+func (c *embeddedSkipList[TKey, T]) loadNext(obj *T, level int) *T {
+	if obj == nil {
+		return c.head[level].Load()
+	}
+	if tower := c.getTower(obj); tower != nil && level < len(tower.next) {
+		return tower.next[level].Load()
+	}
+	return nil
+}
+
+func (c *embeddedSkipList[TKey, T]) storeNext(obj *T, level int, next *T) {
+	c.nextOf(obj, level).Store(next)
+}
+
+func (c *embeddedSkipList[TKey, T]) randomLevel() int {
+	// xorshift64*
+	c.seed ^= c.seed >> 12
+	c.seed ^= c.seed << 25
+	c.seed ^= c.seed >> 27
+	r := c.seed * 0x2545F4914F6CDD1D
+
+	level := 1
+	for level < skipListMaxLevel && r&3 == 0 {
+		level++
+		r >>= 2
+	}
+	return level
+}
+
+// findPredecessor returns the last item with a key less than key, or the last
+// item with a key not greater than key when inclusive is set, along with the
+// item following it.
+func (c *embeddedSkipList[TKey, T]) findPredecessor(key TKey, inclusive bool) (*T, *T) {
+	for {
+		if pred, succ, ok := c.walkPredecessor(key, inclusive); ok {
+			return pred, succ
+		}
+	}
+}
+
+// walkPredecessor performs a single search for findPredecessor. It follows the
+// towers it has loaded rather than reloading them from the items, and gives up
+// when it reaches an item which has been removed or moved too low meanwhile.
+func (c *embeddedSkipList[TKey, T]) walkPredecessor(key TKey, inclusive bool) (*T, *T, bool) {
+	var pred, next *T
+	var predTower *skipListTower[TKey, T]
+	for level := int(c.level.Load()) - 1; level >= 0; level-- {
+		for {
+			if predTower == nil {
+				next = c.head[level].Load()
+			} else {
+				next = predTower.next[level].Load()
+			}
+			if next == nil {
+				break
+			}
+			nextTower := c.getTower(next)
+			if nextTower == nil || level >= len(nextTower.next) {
+				return nil, nil, false
+			}
+			if key < nextTower.key || (!inclusive && !(nextTower.key < key)) {
+				break
+			}
+			pred, predTower = next, nextTower
+		}
+	}
+	return pred, next, true
+}
+
+func (c *embeddedSkipList[TKey, T]) Find(key TKey) *T {
+	return c.FindFirst(key)
+}
+
+func (c *embeddedSkipList[TKey, T]) FindFirst(key TKey) *T {
+	_, walk := c.findPredecessor(key, false)
+	if walk != nil && c.GetKey(walk) == key {
+		return walk
+	}
+	return nil
+}
+
+func (c *embeddedSkipList[TKey, T]) FindNext(cur *T) *T {
+	next := c.Next(cur)
+	if next != nil && c.GetKey(cur) == c.GetKey(next) {
+		return next
+	}
+	return nil
+}
+
+func (c *embeddedSkipList[TKey, T]) FindLowerInclusive(key TKey) *T {
+	pred, walk := c.findPredecessor(key, false)
+	if walk != nil && c.GetKey(walk) == key {
+		return walk
+	}
+	return pred
+}
+
+func (c *embeddedSkipList[TKey, T]) FindUpperInclusive(key TKey) *T {
+	_, succ := c.findPredecessor(key, false)
+	return succ
+}
+
+func (c *embeddedSkipList[TKey, T]) FindLowerExclusive(key TKey) *T {
+	pred, _ := c.findPredecessor(key, false)
+	return pred
+}
+
+func (c *embeddedSkipList[TKey, T]) FindUpperExclusive(key TKey) *T {
+	_, succ := c.findPredecessor(key, true)
+	return succ
+}
+
+func (c *embeddedSkipList[TKey, T]) First() *T {
+	return c.loadNext(nil, 0)
+}
+
+func (c *embeddedSkipList[TKey, T]) Last() *T {
+	return c.tail.Load()
+}
+
+func (c *embeddedSkipList[TKey, T]) Next(cur *T) *T {
+	return c.loadNext(cur, 0)
+}
+
+func (c *embeddedSkipList[TKey, T]) Prev(cur *T) *T {
+	return c.getLink(cur).prev.Load()
+}
+
+func (c *embeddedSkipList[TKey, T]) Insert(key TKey, obj *T) *T {
+	var preds [skipListMaxLevel]*T
+	var pred *T
+	level := int(c.level.Load())
+	for l := level - 1; l >= 0; l-- {
+		for next := c.loadNext(pred, l); next != nil && !(key < c.getTower(next).key); next = c.loadNext(pred, l) {
+			pred = next
+		}
+		preds[l] = pred
+	}
+
+	// a reader may still be walking the tower of a removed item, so it is
+	// never reused
+	height := c.randomLevel()
+	tower := &skipListTower[TKey, T]{
+		key:  key,
+		next: make([]atomic.Pointer[T], height),
+	}
+	for l := 0; l < height; l++ {
+		tower.next[l].Store(c.loadNext(preds[l], l))
+	}
+
+	objLink := c.getLink(obj)
+	objLink.prev.Store(preds[0])
+	objLink.tower.Store(tower)
+	if next := c.loadNext(preds[0], 0); next != nil {
+		c.getLink(next).prev.Store(obj)
+	} else {
+		c.tail.Store(obj)
+	}
+
+	// publish from the bottom up, so an item reachable at some level is
+	// always reachable from every level below it
+	for l := 0; l < height; l++ {
+		c.storeNext(preds[l], l, obj)
+	}
+	if height > level {
+		c.level.Store(int32(height))
+	}
+
+	c.count.Add(1)
+	return obj
+}
+
+func (c *embeddedSkipList[TKey, T]) Remove(obj *T) *T {
+	objLink := c.getLink(obj)
+	tower := objLink.tower.Load()
+	if tower == nil || len(tower.next) == 0 {
+		return nil
+	}
+	key := tower.key
+	height := len(tower.next)
+
+	var preds [skipListMaxLevel]*T
+	var pred *T
+	for l := int(c.level.Load()) - 1; l >= 0; l-- {
+		for next := c.loadNext(pred, l); next != nil && c.getTower(next).key < key; next = c.loadNext(pred, l) {
+			pred = next
+		}
+		if l >= height {
+			continue
+		}
+
+		walk := pred
+		for next := c.loadNext(walk, l); next != obj; next = c.loadNext(walk, l) {
+			if next == nil || key < c.getTower(next).key {
+				return nil
+			}
+			walk = next
+		}
+		preds[l] = walk
+	}
+
+	for l := height - 1; l >= 0; l-- {
+		c.storeNext(preds[l], l, c.loadNext(obj, l))
+	}
+
+	prev := objLink.prev.Load()
+	if next := c.loadNext(obj, 0); next != nil {
+		c.getLink(next).prev.Store(prev)
+	} else {
+		c.tail.Store(prev)
+	}
+
+	// readers still walking the old tower keep it, but the item itself only
+	// keeps its key
+	objLink.prev.Store(nil)
+	objLink.tower.Store(&skipListTower[TKey, T]{key: key})
+
+	c.count.Add(-1)
+	return obj
+}
+
+func (c *embeddedSkipList[TKey, T]) RemoveFirst() *T {
+	head := c.First()
+	if head == nil {
+		return nil
+	}
+	return c.Remove(head)
+}
+
+func (c *embeddedSkipList[TKey, T]) RemoveLast() *T {
+	tail := c.Last()
+	if tail == nil {
+		return nil
+	}
+	return c.Remove(tail)
+}
+
+func (c *embeddedSkipList[TKey, T]) RemoveAll() {
+	for l := range c.head {
+		c.head[l].Store(nil)
+	}
+	c.tail.Store(nil)
+	c.count.Store(0)
+}
+
+func (c *embeddedSkipList[TKey, T]) Move(cur *T, newKey TKey) {
+	c.Remove(cur)
+	c.Insert(newKey, cur)
+}
+
+func (c *embeddedSkipList[TKey, T]) GetKey(obj *T) TKey {
+	if tower := c.getTower(obj); tower != nil {
+		return tower.key
+	}
+	var key TKey
+	return key
+}
+
+func (c *embeddedSkipList[TKey, T]) Count() int {
+	return int(c.count.Load())
+}
+
+func (c *embeddedSkipList[TKey, T]) IsEmpty() bool {
+	return c.First() == nil
+}
+
+func (c *embeddedSkipList[TKey, T]) IsContained(obj *T) bool {
+	if tower := c.getTower(obj); tower == nil || len(tower.next) == 0 {
+		return false
+	}
+	walk := c.FindFirst(c.GetKey(obj))
+	for walk != nil {
+		if walk == obj {
+			return true
+		}
+		walk = c.FindNext(walk)
+	}
+	return false
+}
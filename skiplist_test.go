@@ -0,0 +1,157 @@
+package embedded_test
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type skipListEntry struct {
+	data int
+	link embedded.SkipListLink[int, skipListEntry]
+}
+
+var skipListEntryLinkField = unsafe.Offsetof(skipListEntry{}.link)
+
+func TestEmbeddedSkipList(t *testing.T) {
+	const testSize = 5500
+	s := embedded.NewSkipList[int, skipListEntry](skipListEntryLinkField)
+	for i := testSize - 1; i >= 0; i-- {
+		s.Insert(i, &skipListEntry{data: i})
+	}
+
+	cur := s.Last()
+	for i := testSize - 1; i >= 0; i-- {
+		if cur == nil || cur.data != i {
+			t.Fatal("expected entry not found")
+		}
+		if s.Find(i) != cur {
+			t.Fatal("entry not found by key")
+		}
+		cur = s.Prev(cur)
+	}
+
+	for i := 0; i < testSize; i += 2 {
+		entry := s.Find(i)
+		if s.Remove(entry) == nil {
+			t.Fatal("could not remove contained entry")
+		}
+		if s.IsContained(entry) {
+			t.Fatal("embedded skip list reports that removed item is present")
+		}
+		if s.Remove(entry) != nil {
+			t.Fatal("removed an entry that was not contained")
+		}
+		if s.Next(entry) != nil || s.Prev(entry) != nil || s.GetKey(entry) != i {
+			t.Fatal("removed entry should keep only its key")
+		}
+	}
+
+	if actualCount := s.Count(); actualCount != testSize/2 {
+		t.Fatalf("unexpected skip list count (actual %d != expected %d)", actualCount, testSize/2)
+	}
+
+	if actual := s.FindUpperInclusive(10); actual == nil || actual.data != 11 {
+		t.Fatal("unexpected upper inclusive bound")
+	}
+	if actual := s.FindLowerInclusive(10); actual == nil || actual.data != 9 {
+		t.Fatal("unexpected lower inclusive bound")
+	}
+	if actual := s.FindUpperExclusive(11); actual == nil || actual.data != 13 {
+		t.Fatal("unexpected upper exclusive bound")
+	}
+	if actual := s.FindLowerExclusive(11); actual == nil || actual.data != 9 {
+		t.Fatal("unexpected lower exclusive bound")
+	}
+
+	dup := &skipListEntry{data: -1}
+	s.Insert(11, dup)
+	if first := s.FindFirst(11); first == nil || first.data != 11 || s.FindNext(first) != dup {
+		t.Fatal("duplicate key not found in insertion order")
+	}
+
+	for s.RemoveLast() != nil {
+	}
+	if !s.IsEmpty() || s.First() != nil {
+		t.Fatal("embedded skip list should be empty")
+	}
+}
+
+func TestEmbeddedSkipListConcurrentReaders(t *testing.T) {
+	const testSize = 5500
+	const readers = 4
+	s := embedded.NewSkipList[int, skipListEntry](skipListEntryLinkField)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	errs := make(chan string, readers)
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				prev := -1
+				for cur := s.First(); cur != nil; cur = s.Next(cur) {
+					key := s.GetKey(cur)
+					if key < prev {
+						errs <- "reader observed keys out of order"
+						return
+					}
+					prev = key
+				}
+				if found := s.FindUpperInclusive(testSize / 2); found != nil && s.GetKey(found) < testSize/2 {
+					errs <- "reader observed an out of range bound"
+					return
+				}
+			}
+		}()
+	}
+
+	entries := make([]*skipListEntry, testSize)
+	for i := range entries {
+		entries[i] = &skipListEntry{data: i}
+		s.Insert((i*7919)%testSize, entries[i])
+	}
+	for i := 0; i < testSize; i += 3 {
+		s.Remove(entries[i])
+	}
+	for i := 1; i < testSize; i += 3 {
+		s.Move(entries[i], s.GetKey(entries[i])+testSize)
+	}
+	close(done)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		t.Fatal(err)
+	default:
+	}
+}
+
+func BenchmarkEmbeddedSkipList_Insert(b *testing.B) {
+	s := embedded.NewSkipList[int, skipListEntry](skipListEntryLinkField)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.Insert(i, &skipListEntry{data: i})
+	}
+}
+
+func BenchmarkEmbeddedSkipList_Find(b *testing.B) {
+	const listSize = 1 << 16
+	s := embedded.NewSkipList[int, skipListEntry](skipListEntryLinkField)
+	for i := 0; i < listSize; i++ {
+		s.Insert(i, &skipListEntry{data: i})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Find(i % listSize)
+	}
+}
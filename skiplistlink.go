@@ -0,0 +1,23 @@
+package embedded
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// SkipListLink is a link to the skip list container
+type SkipListLink[TKey, T any] struct {
+	prev  atomic.Pointer[T]
+	tower atomic.Pointer[skipListTower[TKey, T]]
+}
+
+// skipListTower holds the key and forward links an item was inserted with.
+type skipListTower[TKey, T any] struct {
+	key  TKey
+	next []atomic.Pointer[T]
+}
+
+func getSkipListLink[TKey, T any](obj *T, linkFieldOfs uintptr) *SkipListLink[TKey, T] {
+	u := unsafe.Add(unsafe.Pointer(obj), linkFieldOfs)
+	return (*SkipListLink[TKey, T])(u)
+}
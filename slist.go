@@ -0,0 +1,148 @@
+package embedded
+
+// This is a singly-linked list container - it allows for forward iteration
+// over its contents, insertion at either end and removal from the front,
+// which makes it suitable as a stack or a work queue. Its link is a single
+// pointer, but items can only be removed from the front or after a known
+// item.
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+type SList[T any] interface {
+	First() *T
+	Last() *T
+	Next(cur *T) *T
+
+	RemoveFirst() *T
+	RemoveAfter(prev *T) *T
+	RemoveAll()
+
+	InsertFirst(obj *T) *T
+	InsertLast(obj *T) *T
+	InsertAfter(prev, obj *T) *T
+
+	Concat(other SList[T])
+
+	Count() int
+	IsEmpty() bool
+}
+
+func NewSList[T any](linkField uintptr) SList[T] {
+	return &embeddedSList[T]{
+		linkField: linkField,
+	}
+}
+
+type embeddedSList[T any] struct {
+	head      *T
+	tail      *T
+	count     int
+	linkField uintptr
+}
+
+func (c *embeddedSList[T]) getLink(obj *T) *SListLink[T] {
+	return getSListLink(obj, c.linkField)
+}
+
+func (c *embeddedSList[T]) First() *T {
+	return c.head
+}
+
+func (c *embeddedSList[T]) Last() *T {
+	return c.tail
+}
+
+func (c *embeddedSList[T]) Next(cur *T) *T {
+	return c.getLink(cur).next
+}
+
+func (c *embeddedSList[T]) RemoveFirst() *T {
+	return c.RemoveAfter(nil)
+}
+
+// RemoveAfter removes the item following prev, or the first item when
+// prev is nil.
+func (c *embeddedSList[T]) RemoveAfter(prev *T) *T {
+	next := &c.head
+	if prev != nil {
+		next = &c.getLink(prev).next
+	}
+
+	obj := *next
+	if obj == nil {
+		return nil
+	}
+
+	objLink := c.getLink(obj)
+	*next = objLink.next
+	if c.tail == obj {
+		c.tail = prev
+	}
+	objLink.next = nil
+	c.count--
+	return obj
+}
+
+func (c *embeddedSList[T]) RemoveAll() {
+	for cur := c.head; cur != nil; cur = c.head {
+		c.RemoveFirst()
+	}
+}
+
+func (c *embeddedSList[T]) InsertFirst(obj *T) *T {
+	return c.InsertAfter(nil, obj)
+}
+
+func (c *embeddedSList[T]) InsertLast(obj *T) *T {
+	return c.InsertAfter(c.tail, obj)
+}
+
+// InsertAfter inserts obj after prev, or at the front when prev is nil.
+func (c *embeddedSList[T]) InsertAfter(prev, obj *T) *T {
+	next := &c.head
+	if prev != nil {
+		next = &c.getLink(prev).next
+	}
+
+	c.getLink(obj).next = *next
+	*next = obj
+	if c.tail == prev {
+		c.tail = obj
+	}
+	c.count++
+	return obj
+}
+
+// Concat moves all the items of other to the end of this list, leaving
+// other empty.
+func (c *embeddedSList[T]) Concat(other SList[T]) {
+	o, ok := other.(*embeddedSList[T])
+	if !ok || o.linkField != c.linkField {
+		panic("cannot concatenate lists using different links")
+	}
+	if o == c {
+		panic("cannot concatenate a list to itself")
+	}
+	if o.head == nil {
+		return
+	}
+
+	if c.tail == nil {
+		c.head = o.head
+	} else {
+		c.getLink(c.tail).next = o.head
+	}
+	c.tail = o.tail
+	c.count += o.count
+	o.head = nil
+	o.tail = nil
+	o.count = 0
+}
+
+func (c *embeddedSList[T]) Count() int {
+	return c.count
+}
+
+func (c *embeddedSList[T]) IsEmpty() bool {
+	return c.count == 0
+}
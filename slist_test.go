@@ -0,0 +1,100 @@
+package embedded_test
+
+import (
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type slistEntry struct {
+	data int
+	link embedded.SListLink[slistEntry]
+}
+
+var slistEntryLinkField = unsafe.Offsetof(slistEntry{}.link)
+
+func TestEmbeddedSList(t *testing.T) {
+	const testSize = 5500
+	c := embedded.NewSList[slistEntry](slistEntryLinkField)
+	for i := 0; i < testSize; i++ {
+		c.InsertLast(&slistEntry{data: i})
+	}
+	if actualCount := c.Count(); actualCount != testSize {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize)
+	}
+
+	// remove every odd item
+	for cur := c.First(); cur != nil; cur = c.Next(cur) {
+		if removed := c.RemoveAfter(cur); removed == nil || removed.data != cur.data+1 {
+			t.Fatal("unexpected item removed")
+		}
+	}
+	if actualLast := c.Last(); actualLast == nil || actualLast.data != testSize-2 {
+		t.Fatal("unexpected item at end of embedded list")
+	}
+
+	c.InsertAfter(c.Last(), &slistEntry{data: testSize})
+	c.InsertAfter(nil, &slistEntry{data: -2})
+	c.InsertFirst(&slistEntry{data: -4})
+	expected := -4
+	for cur := c.First(); cur != nil; cur = c.Next(cur) {
+		if cur.data != expected {
+			t.Fatalf("mismatched item in embedded list (actual %d != expected %d)", cur.data, expected)
+		}
+		expected += 2
+	}
+	if actualCount := c.Count(); actualCount != testSize/2+3 {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize/2+3)
+	}
+
+	other := embedded.NewSList[slistEntry](slistEntryLinkField)
+	other.InsertLast(&slistEntry{data: testSize + 2})
+	c.Concat(other)
+	if !other.IsEmpty() || other.First() != nil || other.Last() != nil {
+		t.Fatal("concatenated list is not empty")
+	}
+	if actualLast := c.Last(); actualLast == nil || actualLast.data != testSize+2 {
+		t.Fatal("unexpected item at end of embedded list")
+	}
+	other.Concat(c)
+	if actualCount := other.Count(); actualCount != testSize/2+4 {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize/2+4)
+	}
+
+	if actualFirst := other.RemoveFirst(); actualFirst == nil || actualFirst.data != -4 {
+		t.Fatal("unexpected item at front of embedded list")
+	}
+	if other.RemoveAfter(other.Last()) != nil {
+		t.Fatal("unexpected item removed after last")
+	}
+
+	other.RemoveAll()
+	if !other.IsEmpty() || other.First() != nil || other.Last() != nil || other.RemoveFirst() != nil {
+		t.Fatal("embedded list is not empty after RemoveAll")
+	}
+	other.InsertLast(&slistEntry{data: 1})
+	if other.First() != other.Last() || other.Count() != 1 {
+		t.Fatal("unexpected contents after reuse")
+	}
+}
+
+func BenchmarkEmbeddedSList_InsertLast(b *testing.B) {
+	list := embedded.NewSList[slistEntry](slistEntryLinkField)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		list.InsertLast(&slistEntry{data: i})
+	}
+}
+
+func BenchmarkEmbeddedSList_Queue(b *testing.B) {
+	list := embedded.NewSList[slistEntry](slistEntryLinkField)
+	entries := make([]slistEntry, 1024)
+	for i := range entries {
+		list.InsertLast(&entries[i])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list.InsertLast(list.RemoveFirst())
+	}
+}
@@ -0,0 +1,15 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// SListLink is a link to the singly-linked list container
+type SListLink[T any] struct {
+	next *T
+}
+
+func getSListLink[T any](obj *T, linkFieldOfs uintptr) *SListLink[T] {
+	u := unsafe.Add(unsafe.Pointer(obj), linkFieldOfs)
+	return (*SListLink[T])(u)
+}
@@ -0,0 +1,384 @@
+package embedded
+
+// This is a map container with a splay tree internally - every lookup moves
+// the item it finds to the root of the tree, so a small set of frequently
+// accessed items stays cheap to reach.
+// Since lookups restructure the tree, no method of this container is safe to
+// call concurrently with another.
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+type SplayMap[TKey MapKeyType, T any] interface {
+	Map[TKey, T]
+}
+
+func NewSplayMap[TKey MapKeyType, T any](linkField uintptr) SplayMap[TKey, T] {
+	return &embeddedSplayMap[TKey, T]{
+		linkField: linkField,
+	}
+}
+
+type embeddedSplayMap[TKey MapKeyType, T any] struct {
+	root      *T
+	count     int
+	linkField uintptr
+}
+
+func (c *embeddedSplayMap[TKey, T]) getLink(obj *T) *SplayMapLink[TKey, T] {
+	return getSplayMapLink[TKey](obj, c.linkField)
+}
+
+func (c *embeddedSplayMap[TKey, T]) size(obj *T) int {
+	if obj == nil {
+		return 0
+	}
+	return c.getLink(obj).position
+}
+
+// findBound returns the first item with a key not less than key, or the first
+// item with a key greater than key when upper is set.
+func (c *embeddedSplayMap[TKey, T]) findBound(key TKey, upper bool) *T {
+	var found, last *T
+	walk := c.root
+	for walk != nil {
+		last = walk
+		walkLink := c.getLink(walk)
+		if walkLink.key < key || (upper && !(key < walkLink.key)) {
+			walk = walkLink.right
+		} else {
+			found = walk
+			walk = walkLink.left
+		}
+	}
+	if found != nil {
+		c.splay(found)
+	} else if last != nil {
+		c.splay(last)
+	}
+	return found
+}
+
+// findPredecessor returns the last item with a key less than key.
+func (c *embeddedSplayMap[TKey, T]) findPredecessor(key TKey) *T {
+	var found, last *T
+	walk := c.root
+	for walk != nil {
+		last = walk
+		walkLink := c.getLink(walk)
+		if walkLink.key < key {
+			found = walk
+			walk = walkLink.right
+		} else {
+			walk = walkLink.left
+		}
+	}
+	if found != nil {
+		c.splay(found)
+	} else if last != nil {
+		c.splay(last)
+	}
+	return found
+}
+
+func (c *embeddedSplayMap[TKey, T]) Find(key TKey) *T {
+	return c.FindFirst(key)
+}
+
+func (c *embeddedSplayMap[TKey, T]) FindFirst(key TKey) *T {
+	walk := c.findBound(key, false)
+	if walk != nil && c.GetKey(walk) == key {
+		return walk
+	}
+	return nil
+}
+
+func (c *embeddedSplayMap[TKey, T]) FindNext(cur *T) *T {
+	next := c.Next(cur)
+	if next != nil && c.GetKey(cur) == c.GetKey(next) {
+		return next
+	}
+	return nil
+}
+
+func (c *embeddedSplayMap[TKey, T]) FindLowerInclusive(key TKey) *T {
+	walk := c.findBound(key, false)
+	if walk != nil && c.GetKey(walk) == key {
+		return walk
+	}
+	return c.findPredecessor(key)
+}
+
+func (c *embeddedSplayMap[TKey, T]) FindUpperInclusive(key TKey) *T {
+	return c.findBound(key, false)
+}
+
+func (c *embeddedSplayMap[TKey, T]) FindLowerExclusive(key TKey) *T {
+	return c.findPredecessor(key)
+}
+
+func (c *embeddedSplayMap[TKey, T]) FindUpperExclusive(key TKey) *T {
+	return c.findBound(key, true)
+}
+
+func (c *embeddedSplayMap[TKey, T]) First() *T {
+	var prev *T
+	cur := c.root
+	for cur != nil {
+		prev = cur
+		cur = c.getLink(cur).left
+	}
+	return prev
+}
+
+func (c *embeddedSplayMap[TKey, T]) Last() *T {
+	var prev *T
+	cur := c.root
+	for cur != nil {
+		prev = cur
+		cur = c.getLink(cur).right
+	}
+	return prev
+}
+
+func (c *embeddedSplayMap[TKey, T]) Next(cur *T) *T {
+	curLink := c.getLink(cur)
+	if curLink.right != nil {
+		walk := curLink.right
+		for {
+			walkLink := c.getLink(walk)
+			if walkLink.left == nil {
+				break
+			}
+			walk = walkLink.left
+		}
+		return walk
+	}
+
+	curParent := curLink.parent
+	for curParent != nil && c.getLink(curParent).right == cur {
+		cur = curParent
+		curParent = c.getLink(cur).parent
+	}
+	return curParent
+}
+
+func (c *embeddedSplayMap[TKey, T]) Prev(cur *T) *T {
+	curLink := c.getLink(cur)
+	if curLink.left != nil {
+		walk := curLink.left
+		for {
+			walkLink := c.getLink(walk)
+			if walkLink.right == nil {
+				break
+			}
+			walk = walkLink.right
+		}
+		return walk
+	}
+
+	curParent := curLink.parent
+	for curParent != nil && c.getLink(curParent).left == cur {
+		cur = curParent
+		curParent = c.getLink(cur).parent
+	}
+	return curParent
+}
+
+func (c *embeddedSplayMap[TKey, T]) GetPosition(obj *T) int {
+	position := c.size(c.getLink(obj).left)
+	walk := obj
+	for {
+		parent := c.getLink(walk).parent
+		if parent == nil {
+			break
+		}
+		if parentLink := c.getLink(parent); parentLink.right == walk {
+			position += c.size(parentLink.left) + 1
+		}
+		walk = parent
+	}
+	return position
+}
+
+func (c *embeddedSplayMap[TKey, T]) Position(index int) *T {
+	walk := c.root
+	for walk != nil {
+		walkLink := c.getLink(walk)
+		leftSize := c.size(walkLink.left)
+		if index < leftSize {
+			walk = walkLink.left
+		} else if leftSize < index {
+			index -= leftSize + 1
+			walk = walkLink.right
+		} else {
+			c.splay(walk)
+			return walk
+		}
+	}
+	return nil
+}
+
+func (c *embeddedSplayMap[TKey, T]) Insert(key TKey, obj *T) *T {
+	var parent *T
+	parentBranch := &c.root
+	walk := c.root
+	for walk != nil {
+		parent = walk
+		walkLink := c.getLink(walk)
+		walkLink.position++
+		if key < walkLink.key {
+			parentBranch = &walkLink.left
+		} else {
+			parentBranch = &walkLink.right
+		}
+		walk = *parentBranch
+	}
+
+	*parentBranch = obj
+	objLink := c.getLink(obj)
+	objLink.parent = parent
+	objLink.left = nil
+	objLink.right = nil
+	objLink.position = 1
+	objLink.key = key
+	c.count++
+	c.splay(obj)
+	return obj
+}
+
+func (c *embeddedSplayMap[TKey, T]) Remove(obj *T) *T {
+	objLink := c.getLink(obj)
+	if objLink.parent == nil && c.root != obj {
+		return nil
+	}
+
+	c.splay(obj)
+	left := objLink.left
+	right := objLink.right
+
+	if left == nil {
+		c.root = right
+		if right != nil {
+			c.getLink(right).parent = nil
+		}
+	} else {
+		c.getLink(left).parent = nil
+		c.root = left
+		leftMax := c.Last()
+		c.splay(leftMax)
+		leftMaxLink := c.getLink(leftMax)
+		leftMaxLink.right = right
+		leftMaxLink.position += c.size(right)
+		if right != nil {
+			c.getLink(right).parent = leftMax
+		}
+	}
+
+	objLink.parent = nil
+	objLink.left = nil
+	objLink.right = nil
+	objLink.position = 0
+	c.count--
+	return obj
+}
+
+func (c *embeddedSplayMap[TKey, T]) RemoveFirst() *T {
+	head := c.First()
+	if head == nil {
+		return nil
+	}
+	return c.Remove(head)
+}
+
+func (c *embeddedSplayMap[TKey, T]) RemoveLast() *T {
+	tail := c.Last()
+	if tail == nil {
+		return nil
+	}
+	return c.Remove(tail)
+}
+
+func (c *embeddedSplayMap[TKey, T]) Move(cur *T, newKey TKey) {
+	c.Remove(cur)
+	c.Insert(newKey, cur)
+}
+
+func (c *embeddedSplayMap[TKey, T]) GetKey(obj *T) TKey {
+	return c.getLink(obj).key
+}
+
+func (c *embeddedSplayMap[TKey, T]) Count() int {
+	return c.count
+}
+
+func (c *embeddedSplayMap[TKey, T]) IsEmpty() bool {
+	return c.count == 0
+}
+
+func (c *embeddedSplayMap[TKey, T]) RemoveAll() {
+	c.root = nil
+	c.count = 0
+}
+
+func (c *embeddedSplayMap[TKey, T]) IsContained(obj *T) bool {
+	walk := c.FindFirst(c.GetKey(obj))
+	for walk != nil {
+		if walk == obj {
+			return true
+		}
+		walk = c.FindNext(walk)
+	}
+	return false
+}
+
+func (c *embeddedSplayMap[TKey, T]) rotate(cur *T) {
+	curLink := c.getLink(cur)
+	parent := curLink.parent
+	parentLink := c.getLink(parent)
+	grand := parentLink.parent
+
+	if parentLink.left == cur {
+		inner := curLink.right
+		parentLink.left = inner
+		if inner != nil {
+			c.getLink(inner).parent = parent
+		}
+		curLink.right = parent
+	} else {
+		inner := curLink.left
+		parentLink.right = inner
+		if inner != nil {
+			c.getLink(inner).parent = parent
+		}
+		curLink.left = parent
+	}
+
+	parentLink.parent = cur
+	curLink.parent = grand
+	if grand == nil {
+		c.root = cur
+	} else if grandLink := c.getLink(grand); grandLink.left == parent {
+		grandLink.left = cur
+	} else {
+		grandLink.right = cur
+	}
+
+	curLink.position = parentLink.position
+	parentLink.position = c.size(parentLink.left) + c.size(parentLink.right) + 1
+}
+
+func (c *embeddedSplayMap[TKey, T]) splay(cur *T) {
+	curLink := c.getLink(cur)
+	for curLink.parent != nil {
+		parent := curLink.parent
+		parentLink := c.getLink(parent)
+		if grand := parentLink.parent; grand != nil {
+			if (c.getLink(grand).left == parent) == (parentLink.left == cur) {
+				c.rotate(parent)
+			} else {
+				c.rotate(cur)
+			}
+		}
+		c.rotate(cur)
+	}
+}
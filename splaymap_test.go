@@ -0,0 +1,58 @@
+package embedded_test
+
+import (
+	"math/rand"
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type splayMapEntry struct {
+	data int
+	link embedded.SplayMapLink[int, splayMapEntry]
+}
+
+var splayMapEntryLinkField = unsafe.Offsetof(splayMapEntry{}.link)
+
+func TestEmbeddedSplayMap(t *testing.T) {
+	testEmbeddedMap(t, func() embedded.Map[int, splayMapEntry] {
+		return embedded.NewSplayMap[int, splayMapEntry](splayMapEntryLinkField)
+	}, func(data int) *splayMapEntry {
+		return &splayMapEntry{data: data}
+	}, func(obj *splayMapEntry) int {
+		return obj.data
+	})
+}
+
+func BenchmarkEmbeddedSplayMap_Insert(b *testing.B) {
+	m := embedded.NewSplayMap[int, splayMapEntry](splayMapEntryLinkField)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.Insert(i, &splayMapEntry{data: i})
+	}
+}
+
+func BenchmarkEmbeddedSplayMap_FindZipf(b *testing.B) {
+	const mapSize = 1 << 16
+	m := embedded.NewSplayMap[int, splayMapEntry](splayMapEntryLinkField)
+	for _, i := range rand.New(rand.NewSource(1)).Perm(mapSize) {
+		m.Insert(i, &splayMapEntry{data: i})
+	}
+	keys := zipfKeys(mapSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Find(keys[i%len(keys)])
+	}
+}
+
+func zipfKeys(keySpace int) []int {
+	r := rand.New(rand.NewSource(1))
+	z := rand.NewZipf(r, 1.1, 1, uint64(keySpace-1))
+	perm := r.Perm(keySpace)
+	keys := make([]int, 1<<16)
+	for i := range keys {
+		keys[i] = perm[z.Uint64()]
+	}
+	return keys
+}
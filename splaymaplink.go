@@ -0,0 +1,19 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// SplayMapLink is a link to the splay map container
+type SplayMapLink[TKey, T any] struct {
+	key      TKey
+	parent   *T
+	left     *T
+	right    *T
+	position int
+}
+
+func getSplayMapLink[TKey, T any](obj *T, linkFieldOfs uintptr) *SplayMapLink[TKey, T] {
+	u := unsafe.Add(unsafe.Pointer(obj), linkFieldOfs)
+	return (*SplayMapLink[TKey, T])(u)
+}
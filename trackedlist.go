@@ -0,0 +1,240 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// This is a double-linked list container which records in each link the list
+// containing the item - IsContained is exact and O(1), and removing, moving or
+// splicing an item which is not in this list, or inserting one which is
+// already in a list, panics instead of corrupting the lists. Keeping the
+// owners current makes SpliceAll, MergeSorted and RemoveAll O(n).
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+func NewTrackedList[T any](linkField uintptr) List[T] {
+	var tll TrackedListLink[T]
+	return &embeddedTrackedList[T]{
+		list:      NewList[T](linkField + unsafe.Offsetof(tll.list)),
+		linkField: linkField,
+	}
+}
+
+type embeddedTrackedList[T any] struct {
+	list      List[T]
+	linkField uintptr
+}
+
+func (c *embeddedTrackedList[T]) getLink(obj *T) *TrackedListLink[T] {
+	return getTrackedListLink(obj, c.linkField)
+}
+
+func (c *embeddedTrackedList[T]) checkContained(obj *T) {
+	if !c.IsContained(obj) {
+		panic("item is not contained in this list")
+	}
+}
+
+func (c *embeddedTrackedList[T]) checkDest(dest *T) {
+	if dest != nil && !c.IsContained(dest) {
+		panic("destination item is not contained in this list")
+	}
+}
+
+func (c *embeddedTrackedList[T]) checkNotContained(obj *T) {
+	if c.getLink(obj).owner != nil {
+		panic("cannot insert an item which is already contained in a list")
+	}
+}
+
+// setOwner records owner in the links of the items from first through last.
+func (c *embeddedTrackedList[T]) setOwner(first, last *T, owner *embeddedTrackedList[T]) {
+	for cur := first; ; cur = c.list.Next(cur) {
+		c.getLink(cur).owner = owner
+		if cur == last {
+			break
+		}
+	}
+}
+
+func (c *embeddedTrackedList[T]) First() *T {
+	return c.list.First()
+}
+
+func (c *embeddedTrackedList[T]) Last() *T {
+	return c.list.Last()
+}
+
+func (c *embeddedTrackedList[T]) Next(cur *T) *T {
+	return c.list.Next(cur)
+}
+
+func (c *embeddedTrackedList[T]) Prev(cur *T) *T {
+	return c.list.Prev(cur)
+}
+
+func (c *embeddedTrackedList[T]) Position(index int) *T {
+	return c.list.Position(index)
+}
+
+func (c *embeddedTrackedList[T]) Remove(obj *T) *T {
+	c.checkContained(obj)
+	c.getLink(obj).owner = nil
+	return c.list.Remove(obj)
+}
+
+func (c *embeddedTrackedList[T]) RemoveFirst() *T {
+	obj := c.list.RemoveFirst()
+	if obj != nil {
+		c.getLink(obj).owner = nil
+	}
+	return obj
+}
+
+func (c *embeddedTrackedList[T]) RemoveLast() *T {
+	obj := c.list.RemoveLast()
+	if obj != nil {
+		c.getLink(obj).owner = nil
+	}
+	return obj
+}
+
+func (c *embeddedTrackedList[T]) RemoveAll() {
+	for cur := c.list.First(); cur != nil; cur = c.list.Next(cur) {
+		c.getLink(cur).owner = nil
+	}
+	c.list.RemoveAll()
+}
+
+func (c *embeddedTrackedList[T]) InsertFirst(cur *T) *T {
+	c.checkNotContained(cur)
+	c.getLink(cur).owner = c
+	return c.list.InsertFirst(cur)
+}
+
+func (c *embeddedTrackedList[T]) InsertLast(cur *T) *T {
+	c.checkNotContained(cur)
+	c.getLink(cur).owner = c
+	return c.list.InsertLast(cur)
+}
+
+func (c *embeddedTrackedList[T]) InsertAfter(prev, cur *T) *T {
+	c.checkDest(prev)
+	c.checkNotContained(cur)
+	c.getLink(cur).owner = c
+	return c.list.InsertAfter(prev, cur)
+}
+
+func (c *embeddedTrackedList[T]) InsertBefore(after, cur *T) *T {
+	c.checkDest(after)
+	c.checkNotContained(cur)
+	c.getLink(cur).owner = c
+	return c.list.InsertBefore(after, cur)
+}
+
+func (c *embeddedTrackedList[T]) MoveFirst(cur *T) {
+	c.checkContained(cur)
+	c.list.MoveFirst(cur)
+}
+
+func (c *embeddedTrackedList[T]) MoveLast(cur *T) {
+	c.checkContained(cur)
+	c.list.MoveLast(cur)
+}
+
+func (c *embeddedTrackedList[T]) MoveAfter(dest, cur *T) {
+	c.checkDest(dest)
+	c.checkContained(cur)
+	c.list.MoveAfter(dest, cur)
+}
+
+func (c *embeddedTrackedList[T]) MoveBefore(dest, cur *T) {
+	c.checkDest(dest)
+	c.checkContained(cur)
+	c.list.MoveBefore(dest, cur)
+}
+
+func (c *embeddedTrackedList[T]) SpliceAfter(dest *T, src List[T], first, last *T) {
+	s := c.spliceSource(src)
+	c.checkDest(dest)
+	s.checkContained(first)
+	s.checkContained(last)
+	c.list.SpliceAfter(dest, s.list, first, last)
+	c.setOwner(first, last, c)
+}
+
+func (c *embeddedTrackedList[T]) SpliceAfterCount(dest *T, src List[T], first, last *T, count int) {
+	s := c.spliceSource(src)
+	c.checkDest(dest)
+	s.checkContained(first)
+	s.checkContained(last)
+	c.list.SpliceAfterCount(dest, s.list, first, last, count)
+	c.setOwner(first, last, c)
+}
+
+func (c *embeddedTrackedList[T]) SpliceAll(dest *T, src List[T]) {
+	s := c.spliceSource(src)
+	c.checkDest(dest)
+	first, last := s.list.First(), s.list.Last()
+	c.list.SpliceAll(dest, s.list)
+	if first != nil {
+		c.setOwner(first, last, c)
+	}
+}
+
+func (c *embeddedTrackedList[T]) spliceSource(src List[T]) *embeddedTrackedList[T] {
+	s, ok := src.(*embeddedTrackedList[T])
+	if !ok || s.linkField != c.linkField {
+		panic("cannot splice between lists using different links")
+	}
+	return s
+}
+
+func (c *embeddedTrackedList[T]) Sort(less func(a, b *T) bool) {
+	c.list.Sort(less)
+}
+
+func (c *embeddedTrackedList[T]) MergeSorted(other List[T], less func(a, b *T) bool) {
+	s := c.spliceSource(other)
+	for cur := s.list.First(); cur != nil; cur = s.list.Next(cur) {
+		c.getLink(cur).owner = c
+	}
+	c.list.MergeSorted(s.list, less)
+}
+
+func (c *embeddedTrackedList[T]) Reverse() {
+	c.list.Reverse()
+}
+
+func (c *embeddedTrackedList[T]) Rotate(n int) {
+	c.list.Rotate(n)
+}
+
+func (c *embeddedTrackedList[T]) RotateTo(obj *T) {
+	c.checkContained(obj)
+	c.list.RotateTo(obj)
+}
+
+func (c *embeddedTrackedList[T]) SplitAfter(obj *T) List[T] {
+	c.checkDest(obj)
+	other := &embeddedTrackedList[T]{
+		list:      c.list.SplitAfter(obj),
+		linkField: c.linkField,
+	}
+	if first := other.list.First(); first != nil {
+		other.setOwner(first, other.list.Last(), other)
+	}
+	return other
+}
+
+func (c *embeddedTrackedList[T]) Count() int {
+	return c.list.Count()
+}
+
+func (c *embeddedTrackedList[T]) IsEmpty() bool {
+	return c.list.IsEmpty()
+}
+
+func (c *embeddedTrackedList[T]) IsContained(cur *T) bool {
+	return c.getLink(cur).owner == c
+}
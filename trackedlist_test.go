@@ -0,0 +1,103 @@
+package embedded_test
+
+import (
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type trackedListEntry struct {
+	data int
+	link embedded.TrackedListLink[trackedListEntry]
+}
+
+var trackedListEntryLinkField = unsafe.Offsetof(trackedListEntry{}.link)
+
+func TestEmbeddedTrackedList(t *testing.T) {
+	const testSize = 100
+	a := embedded.NewTrackedList[trackedListEntry](trackedListEntryLinkField)
+	b := embedded.NewTrackedList[trackedListEntry](trackedListEntryLinkField)
+	for i := 0; i < testSize; i++ {
+		a.InsertLast(&trackedListEntry{data: i})
+	}
+	inB := b.InsertLast(&trackedListEntry{data: testSize})
+	absent := &trackedListEntry{data: -1}
+
+	for cur := a.First(); cur != nil; cur = a.Next(cur) {
+		if !a.IsContained(cur) || b.IsContained(cur) {
+			t.Fatal("item reported in the wrong list")
+		}
+	}
+	if a.IsContained(inB) || a.IsContained(absent) {
+		t.Fatal("embedded list reports that foreign item is present")
+	}
+
+	expectPanic(t, func() { a.Remove(inB) })
+	expectPanic(t, func() { a.Remove(absent) })
+	expectPanic(t, func() { a.MoveFirst(inB) })
+	expectPanic(t, func() { a.MoveAfter(inB, a.First()) })
+	expectPanic(t, func() { a.InsertLast(inB) })
+	expectPanic(t, func() { a.InsertAfter(inB, absent) })
+	expectPanic(t, func() { a.RotateTo(absent) })
+	expectPanic(t, func() { b.SpliceAfter(nil, a, inB, inB) })
+	if actualCount := a.Count(); actualCount != testSize {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize)
+	}
+	if actualCount := b.Count(); actualCount != 1 {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, 1)
+	}
+
+	removed := a.Remove(a.First())
+	if a.IsContained(removed) {
+		t.Fatal("embedded list reports that removed item is present")
+	}
+	b.InsertFirst(removed)
+	if !b.IsContained(removed) {
+		t.Fatal("embedded list reports that inserted item is not present")
+	}
+
+	b.SpliceAfter(b.Last(), a, a.Position(10), a.Position(19))
+	for cur := b.First(); cur != nil; cur = b.Next(cur) {
+		if !b.IsContained(cur) || a.IsContained(cur) {
+			t.Fatal("spliced item reported in the wrong list")
+		}
+	}
+
+	c := a.SplitAfter(a.Position(49))
+	for cur := c.First(); cur != nil; cur = c.Next(cur) {
+		if !c.IsContained(cur) || a.IsContained(cur) {
+			t.Fatal("split item reported in the wrong list")
+		}
+	}
+
+	a.SpliceAll(nil, c)
+	a.MergeSorted(b, func(x, y *trackedListEntry) bool {
+		return x.data < y.data
+	})
+	if actualCount := a.Count(); actualCount != testSize+1 {
+		t.Fatalf("unexpected list count (actual %d != expected %d)", actualCount, testSize+1)
+	}
+	for cur := a.First(); cur != nil; cur = a.Next(cur) {
+		if !a.IsContained(cur) || b.IsContained(cur) || c.IsContained(cur) {
+			t.Fatal("merged item reported in the wrong list")
+		}
+	}
+
+	first := a.First()
+	a.RemoveAll()
+	if a.IsContained(first) || a.IsContained(inB) {
+		t.Fatal("embedded list reports that removed item is present")
+	}
+	b.InsertLast(inB)
+}
+
+func expectPanic(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,16 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// TrackedListLink is a link to the tracked list container
+type TrackedListLink[T any] struct {
+	list  ListLink[T]
+	owner *embeddedTrackedList[T]
+}
+
+func getTrackedListLink[T any](obj *T, linkFieldOfs uintptr) *TrackedListLink[T] {
+	u := unsafe.Add(unsafe.Pointer(obj), linkFieldOfs)
+	return (*TrackedListLink[T])(u)
+}
@@ -0,0 +1,148 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// This is a map container which records in each link the map containing the
+// item - IsContained is exact and O(1) instead of a key search, and removing
+// or moving an item which is not in this map, or inserting one which is
+// already in a map, panics instead of corrupting the maps. Keeping the owners
+// current makes RemoveAll O(n).
+// This cointainer does not take ownership of its contents, so the application
+// must remove items manually.
+
+func NewTrackedMap[TKey MapKeyType, T any](linkField uintptr) Map[TKey, T] {
+	var tml TrackedMapLink[TKey, T]
+	return &embeddedTrackedMap[TKey, T]{
+		tree:      NewMap[TKey, T](linkField + unsafe.Offsetof(tml.link)),
+		linkField: linkField,
+	}
+}
+
+type embeddedTrackedMap[TKey MapKeyType, T any] struct {
+	tree      Map[TKey, T]
+	linkField uintptr
+}
+
+func (c *embeddedTrackedMap[TKey, T]) getLink(obj *T) *TrackedMapLink[TKey, T] {
+	return getTrackedMapLink[TKey](obj, c.linkField)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) checkContained(obj *T) {
+	if !c.IsContained(obj) {
+		panic("item is not contained in this map")
+	}
+}
+
+func (c *embeddedTrackedMap[TKey, T]) First() *T {
+	return c.tree.First()
+}
+
+func (c *embeddedTrackedMap[TKey, T]) Last() *T {
+	return c.tree.Last()
+}
+
+func (c *embeddedTrackedMap[TKey, T]) Next(cur *T) *T {
+	return c.tree.Next(cur)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) Prev(cur *T) *T {
+	return c.tree.Prev(cur)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) Position(index int) *T {
+	return c.tree.Position(index)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) Count() int {
+	return c.tree.Count()
+}
+
+func (c *embeddedTrackedMap[TKey, T]) Remove(obj *T) *T {
+	c.checkContained(obj)
+	c.getLink(obj).owner = nil
+	return c.tree.Remove(obj)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) RemoveFirst() *T {
+	obj := c.tree.RemoveFirst()
+	if obj != nil {
+		c.getLink(obj).owner = nil
+	}
+	return obj
+}
+
+func (c *embeddedTrackedMap[TKey, T]) RemoveLast() *T {
+	obj := c.tree.RemoveLast()
+	if obj != nil {
+		c.getLink(obj).owner = nil
+	}
+	return obj
+}
+
+func (c *embeddedTrackedMap[TKey, T]) RemoveAll() {
+	for cur := c.tree.First(); cur != nil; cur = c.tree.Next(cur) {
+		c.getLink(cur).owner = nil
+	}
+	c.tree.RemoveAll()
+}
+
+func (c *embeddedTrackedMap[TKey, T]) Insert(key TKey, obj *T) *T {
+	objLink := c.getLink(obj)
+	if objLink.owner != nil {
+		panic("cannot insert an item which is already contained in a map")
+	}
+	objLink.owner = c
+	return c.tree.Insert(key, obj)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) Move(obj *T, newKey TKey) {
+	c.checkContained(obj)
+	c.tree.Move(obj, newKey)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) GetKey(obj *T) TKey {
+	return c.tree.GetKey(obj)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) IsEmpty() bool {
+	return c.tree.IsEmpty()
+}
+
+func (c *embeddedTrackedMap[TKey, T]) IsContained(obj *T) bool {
+	return c.getLink(obj).owner == c
+}
+
+func (c *embeddedTrackedMap[TKey, T]) GetPosition(obj *T) int {
+	c.checkContained(obj)
+	return c.tree.GetPosition(obj)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) Find(key TKey) *T {
+	return c.tree.Find(key)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) FindFirst(key TKey) *T {
+	return c.tree.FindFirst(key)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) FindNext(cur *T) *T {
+	return c.tree.FindNext(cur)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) FindLowerInclusive(key TKey) *T {
+	return c.tree.FindLowerInclusive(key)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) FindUpperInclusive(key TKey) *T {
+	return c.tree.FindUpperInclusive(key)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) FindLowerExclusive(key TKey) *T {
+	return c.tree.FindLowerExclusive(key)
+}
+
+func (c *embeddedTrackedMap[TKey, T]) FindUpperExclusive(key TKey) *T {
+	return c.tree.FindUpperExclusive(key)
+}
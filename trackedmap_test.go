@@ -0,0 +1,61 @@
+package embedded_test
+
+import (
+	"testing"
+	"unsafe"
+
+	embedded "github.com/heucuva/go-embedded-container"
+)
+
+type trackedMapEntry struct {
+	data int
+	link embedded.TrackedMapLink[int, trackedMapEntry]
+}
+
+var trackedMapEntryLinkField = unsafe.Offsetof(trackedMapEntry{}.link)
+
+func TestEmbeddedTrackedMap(t *testing.T) {
+	testEmbeddedMap(t, func() embedded.Map[int, trackedMapEntry] {
+		return embedded.NewTrackedMap[int, trackedMapEntry](trackedMapEntryLinkField)
+	}, func(data int) *trackedMapEntry {
+		return &trackedMapEntry{data: data}
+	}, func(obj *trackedMapEntry) int {
+		return obj.data
+	})
+}
+
+func TestEmbeddedTrackedMapMisuse(t *testing.T) {
+	a := embedded.NewTrackedMap[int, trackedMapEntry](trackedMapEntryLinkField)
+	b := embedded.NewTrackedMap[int, trackedMapEntry](trackedMapEntryLinkField)
+	for i := 0; i < 100; i++ {
+		a.Insert(i, &trackedMapEntry{data: i})
+	}
+
+	// same key as an item of a, but in b
+	inB := b.Insert(50, &trackedMapEntry{data: 50})
+	absent := &trackedMapEntry{data: 50}
+	if a.IsContained(inB) || a.IsContained(absent) || !b.IsContained(inB) {
+		t.Fatal("item reported in the wrong map")
+	}
+
+	expectPanic(t, func() { a.Remove(inB) })
+	expectPanic(t, func() { a.Remove(absent) })
+	expectPanic(t, func() { a.Move(inB, 1000) })
+	expectPanic(t, func() { a.Insert(50, inB) })
+	expectPanic(t, func() { a.GetPosition(absent) })
+	if actualCount := a.Count(); actualCount != 100 {
+		t.Fatalf("unexpected map count (actual %d != expected %d)", actualCount, 100)
+	}
+
+	b.Insert(10, b.Remove(inB))
+	if actualKey := b.GetKey(inB); actualKey != 10 || !b.IsContained(inB) {
+		t.Fatal("reinserted item not found")
+	}
+
+	first := a.First()
+	a.RemoveAll()
+	if a.IsContained(first) {
+		t.Fatal("embedded map reports that removed item is present")
+	}
+	b.Insert(0, first)
+}
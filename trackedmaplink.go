@@ -0,0 +1,16 @@
+package embedded
+
+import (
+	"unsafe"
+)
+
+// TrackedMapLink is a link to the tracked map container
+type TrackedMapLink[TKey MapKeyType, T any] struct {
+	link  MapLink[TKey, T]
+	owner *embeddedTrackedMap[TKey, T]
+}
+
+func getTrackedMapLink[TKey MapKeyType, T any](obj *T, linkFieldOfs uintptr) *TrackedMapLink[TKey, T] {
+	u := unsafe.Add(unsafe.Pointer(obj), linkFieldOfs)
+	return (*TrackedMapLink[TKey, T])(u)
+}